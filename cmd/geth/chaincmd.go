@@ -131,6 +131,9 @@ if one is set.  Otherwise it prints the genesis from the datadir.`,
 	}
 )
 
+// importChain and exportChain are not ported yet, they need the chain from
+// utils.MakeChain. The block transfer is implemented by utils.ImportChain and
+// utils.ExportChain, which take a context canceled by utils.InterruptContext.
 func importChain(context *cli.Context) error {
 
 }
@@ -0,0 +1,172 @@
+// Command rlpcompat checks that two versions of a struct type have compatible
+// RLP encodings. See package rlp/rlpcompat for the changes it reports.
+//
+// The versions are read from two package directories, or from a package
+// directory and a git revision of it:
+//
+//	rlpcompat -type Header -old ../v1/core/types -new core/types
+//	rlpcompat -type Header -rev HEAD~1 -new core/types
+//
+// The exit status is 1 if any breaking change is found.
+package main
+
+import (
+	"archive/tar"
+	"awesomeProject/rlp/rlpcompat"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run is the body of main. It returns the exit status, so deferred cleanup
+// happens before the process exits.
+func run() int {
+	var (
+		typenames = flag.String("type", "", "comma-separated list of types to check")
+		oldDir    = flag.String("old", "", "package directory of the old version")
+		oldRev    = flag.String("rev", "", "git revision of the old version, used instead of -old")
+		newDir    = flag.String("new", ".", "package directory of the new version")
+	)
+	flag.Parse()
+
+	if *typenames == "" {
+		return fail("missing -type")
+	}
+	if (*oldDir == "") == (*oldRev == "") {
+		return fail("need exactly one of -old and -rev")
+	}
+	if *oldRev != "" {
+		dir, cleanup, err := checkoutRevision(*newDir, *oldRev)
+		if err != nil {
+			return fail(err)
+		}
+		defer cleanup()
+		*oldDir = dir
+	}
+
+	// Generated encoders are excluded, they follow the struct layout and
+	// would hide it otherwise.
+	build.Default.BuildTags = append(build.Default.BuildTags, "norlpgen")
+	oldVersion, err := rlpcompat.Load(*oldDir)
+	if err != nil {
+		return fail("old version:", err)
+	}
+	newVersion, err := rlpcompat.Load(*newDir)
+	if err != nil {
+		return fail("new version:", err)
+	}
+
+	var issues []string
+	for _, name := range strings.Split(*typenames, ",") {
+		found, err := rlpcompat.Check(oldVersion, newVersion, strings.TrimSpace(name))
+		if err != nil {
+			return fail(err)
+		}
+		issues = append(issues, found...)
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// fail prints args to stderr and returns the exit status for errors.
+func fail(args ...interface{}) int {
+	fmt.Fprintln(os.Stderr, args...)
+	return 1
+}
+
+// checkoutRevision extracts the repository containing dir at the given
+// revision into a temporary directory. It returns the location of dir in
+// the extracted tree.
+func checkoutRevision(dir, rev string) (string, func(), error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+	tmp, err := os.MkdirTemp("", "rlpcompat-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	cmd := exec.Command("git", "archive", "--format=tar", rev)
+	cmd.Dir = strings.TrimSpace(top)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	archive, err := cmd.Output()
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("git archive %s: %v: %s", rev, err, strings.TrimSpace(stderr.String()))
+	}
+	if err := extractTar(tmp, bytes.NewReader(archive)); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return filepath.Join(tmp, strings.TrimSpace(prefix)), cleanup, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func extractTar(dst string, r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name := filepath.Join(dst, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(name, filepath.Clean(dst)+string(filepath.Separator)) {
+			return errors.New("invalid path in archive: " + hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(name, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,341 @@
+// Command rlpdump is a pretty-printer for RLP data.
+//
+// Input is read from the file given as argument, from the -hex flag or from
+// standard input. Every top-level value in the input is printed as an
+// indented tree of lists and strings. Encodings which are valid but not
+// canonical are flagged next to the value instead of aborting the dump.
+//
+// With -query, only the values selected by the query path are printed, see
+// package rlp/query for the syntax. The query is run against every
+// top-level value, which makes it easy to pull fields out of exported
+// chain files:
+//
+//	rlpdump -query '[0][8]' -render int chain.rlp
+package main
+
+import (
+	"awesomeProject/rlp"
+	"awesomeProject/rlp/query"
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+)
+
+var (
+	hexMode     = flag.String("hex", "", "dump given hex data")
+	renderMode  = flag.String("render", "auto", "string rendering: auto, hex, ascii or int")
+	showOffsets = flag.Bool("offsets", false, "prefix every line with the input offset of the value")
+	maxDepth    = flag.Int("depth", 0, "don't expand lists nested deeper than this (0 = unlimited)")
+	single      = flag.Bool("single", false, "print only the first top-level value")
+	queryPath   = flag.String("query", "", "print only the values selected by the query `path`")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[-hex HEX] [-render MODE] [-offsets] [-depth N] [-single] [-query PATH] [FILENAME]")
+		flag.PrintDefaults()
+		fmt.Fprintln(os.Stderr, `
+Dumps RLP data from the given file in readable form.
+If the filename is omitted or "-", data is read from stdin.`)
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	switch *renderMode {
+	case "auto", "hex", "ascii", "int":
+	default:
+		die("invalid -render mode", *renderMode)
+	}
+
+	data, err := readInput()
+	if err != nil {
+		die(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	d := &dumper{out: out, render: *renderMode, offsets: *showOffsets, maxDepth: *maxDepth}
+	if *queryPath != "" {
+		err = d.dumpQuery(data, *queryPath, *single)
+	} else {
+		err = d.dumpAll(data, *single)
+	}
+	out.Flush()
+	if err != nil {
+		die(err)
+	}
+}
+
+func readInput() ([]byte, error) {
+	if *hexMode != "" {
+		s := strings.TrimSpace(*hexMode)
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex: %v", err)
+		}
+		return data, nil
+	}
+
+	var r io.Reader
+	switch {
+	case flag.NArg() == 0 || flag.Arg(0) == "-":
+		r = os.Stdin
+	case flag.NArg() == 1:
+		fd, err := os.Open(flag.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+		r = fd
+	default:
+		fmt.Fprintln(os.Stderr, "Error: too many arguments")
+		flag.Usage()
+		os.Exit(2)
+	}
+	return io.ReadAll(r)
+}
+
+// dumper prints RLP values. It works on the raw input instead of using
+// rlp.Stream, so values the decoder would reject as non-canonical can
+// still be displayed.
+type dumper struct {
+	out      io.Writer
+	render   string
+	offsets  bool
+	maxDepth int
+}
+
+// dumpAll prints all top-level values in data.
+func (d *dumper) dumpAll(data []byte, single bool) error {
+	for pos := 0; pos < len(data); {
+		if pos > 0 {
+			fmt.Fprintln(d.out)
+		}
+		n, err := d.dump(data[pos:], pos, 0, "")
+		if err != nil {
+			return err
+		}
+		pos += n
+		if single {
+			if pos < len(data) {
+				fmt.Fprintf(d.out, "\n%d bytes of trailing data\n", len(data)-pos)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// dumpQuery runs the query against all top-level values in data and prints
+// the results. Offsets are not known for computed values, so results which
+// are not part of the input are printed without offset.
+func (d *dumper) dumpQuery(data []byte, path string, single bool) error {
+	q, err := query.Compile(path)
+	if err != nil {
+		return err
+	}
+	for pos := 0; pos < len(data); {
+		_, _, rest, err := rlp.Split(data[pos:])
+		if err != nil {
+			return fmt.Errorf("at offset %d: %v", pos, err)
+		}
+		size := len(data) - pos - len(rest)
+		value := data[pos : pos+size]
+		results, err := q.Eval(value)
+		if err != nil {
+			return fmt.Errorf("value at offset %d: %v", pos, err)
+		}
+		for _, r := range results {
+			if _, err := d.dump(r, offsetIn(data, r), 0, ""); err != nil {
+				return err
+			}
+		}
+		pos += size
+		if single {
+			break
+		}
+	}
+	return nil
+}
+
+// offsetIn returns the position of sub in b, or -1 if sub is not a subslice of b.
+func offsetIn(b, sub []byte) int {
+	if len(sub) == 0 || cap(sub) > cap(b) {
+		return -1
+	}
+	off := cap(b) - cap(sub)
+	if off+len(sub) > len(b) || &b[off] != &sub[0] {
+		return -1
+	}
+	return off
+}
+
+// dump prints the value at the start of b, which is located at the given
+// offset in the input. It returns the size of the value.
+func (d *dumper) dump(b []byte, offset, depth int, suffix string) (int, error) {
+	h, err := readHeader(b)
+	if err != nil {
+		return 0, fmt.Errorf("at offset %d: %v", offset, err)
+	}
+	size := int(h.tagsize + h.contentsize)
+	content := b[h.tagsize:size]
+
+	switch h.kind {
+	case rlp.Byte, rlp.String:
+		s, issue := d.renderString(content)
+		if issue == "" {
+			issue = h.issue
+		}
+		d.line(offset, depth, s+suffix, issue)
+
+	case rlp.List:
+		switch {
+		case len(content) == 0:
+			d.line(offset, depth, "[]"+suffix, h.issue)
+		case d.maxDepth > 0 && depth >= d.maxDepth:
+			s := fmt.Sprintf("[...%d bytes]%s", len(content), suffix)
+			d.line(offset, depth, s, h.issue)
+		default:
+			d.line(offset, depth, "[", h.issue)
+			pos := offset + int(h.tagsize)
+			for len(content) > 0 {
+				if offset < 0 {
+					pos = -1
+				}
+				n, err := d.dump(content, pos, depth+1, ",")
+				if err != nil {
+					return 0, err
+				}
+				content = content[n:]
+				pos += n
+			}
+			d.line(-1, depth, "]"+suffix, "")
+		}
+	}
+	return size, nil
+}
+
+func (d *dumper) line(offset, depth int, s, issue string) {
+	if d.offsets {
+		if offset >= 0 {
+			fmt.Fprintf(d.out, "%08x: ", offset)
+		} else {
+			fmt.Fprint(d.out, "          ")
+		}
+	}
+	fmt.Fprint(d.out, strings.Repeat("  ", depth), s)
+	if issue != "" {
+		fmt.Fprintf(d.out, "  <non-canonical: %s>", issue)
+	}
+	fmt.Fprintln(d.out)
+}
+
+// renderString formats the content of a string value. For integers, it
+// also reports leading zero bytes.
+func (d *dumper) renderString(b []byte) (s, issue string) {
+	switch d.render {
+	case "hex":
+		return hexString(b), ""
+	case "ascii":
+		return fmt.Sprintf("%q", b), ""
+	case "int":
+		if len(b) > 0 && b[0] == 0 {
+			issue = "integer has leading zero bytes"
+		}
+		return new(big.Int).SetBytes(b).String(), issue
+	default:
+		if len(b) > 0 && isASCII(b) {
+			return fmt.Sprintf("%q", b), ""
+		}
+		return hexString(b), ""
+	}
+}
+
+func hexString(b []byte) string {
+	if len(b) == 0 {
+		return `""`
+	}
+	return hex.EncodeToString(b)
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c < 32 || c > 126 {
+			return false
+		}
+	}
+	return true
+}
+
+type header struct {
+	kind        rlp.Kind
+	tagsize     uint64
+	contentsize uint64
+	issue       string // describes non-canonical encoding of the header
+}
+
+var errTooShort = errors.New("value size exceeds available input")
+
+// readHeader parses the RLP header at the start of buf. Unlike the decoder
+// in package rlp, it accepts non-canonical sizes and reports them in the
+// issue field.
+func readHeader(buf []byte) (h header, err error) {
+	if len(buf) == 0 {
+		return h, io.ErrUnexpectedEOF
+	}
+	b := buf[0]
+	switch {
+	case b < 0x80:
+		h.kind, h.tagsize, h.contentsize = rlp.Byte, 0, 1
+	case b < 0xB8:
+		h.kind, h.tagsize, h.contentsize = rlp.String, 1, uint64(b-0x80)
+		if h.contentsize == 1 && len(buf) > 1 && buf[1] < 0x80 {
+			h.issue = "single byte below 0x80 encoded as string"
+		}
+	case b < 0xC0:
+		h.kind, h.tagsize = rlp.String, uint64(b-0xB7)+1
+		h.contentsize, h.issue, err = readSize(buf[1:], int(b-0xB7))
+	case b < 0xF8:
+		h.kind, h.tagsize, h.contentsize = rlp.List, 1, uint64(b-0xC0)
+	default:
+		h.kind, h.tagsize = rlp.List, uint64(b-0xF7)+1
+		h.contentsize, h.issue, err = readSize(buf[1:], int(b-0xF7))
+	}
+	if err != nil {
+		return h, err
+	}
+	if h.contentsize > uint64(len(buf))-h.tagsize {
+		return h, errTooShort
+	}
+	return h, nil
+}
+
+// readSize reads the big-endian size of a long string or list.
+func readSize(b []byte, slen int) (size uint64, issue string, err error) {
+	if slen > len(b) {
+		return 0, "", io.ErrUnexpectedEOF
+	}
+	if b[0] == 0 {
+		issue = "size has leading zero bytes"
+	}
+	for _, c := range b[:slen] {
+		size = size<<8 | uint64(c)
+	}
+	if size < 56 && issue == "" {
+		issue = fmt.Sprintf("size %d uses long form", size)
+	}
+	return size, issue, nil
+}
+
+func die(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
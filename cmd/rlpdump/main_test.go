@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		input string
+		d     dumper
+		want  string
+	}{
+		{
+			input: "C883646F67C20180C0",
+			want: `[
+  "dog",
+  [
+    01,
+    "",
+  ],
+  [],
+]
+`,
+		},
+		{
+			input: "C883646F67C20180C0",
+			d:     dumper{offsets: true},
+			want: `00000000: [
+00000001:   "dog",
+00000005:   [
+00000006:     01,
+00000007:     "",
+            ],
+00000008:   [],
+          ]
+`,
+		},
+		{
+			input: "C883646F67C20180C0",
+			d:     dumper{maxDepth: 1},
+			want: `[
+  "dog",
+  [...2 bytes],
+  [],
+]
+`,
+		},
+		{
+			input: "C883646F67C20180C0",
+			d:     dumper{render: "hex"},
+			want: `[
+  646f67,
+  [
+    01,
+    "",
+  ],
+  [],
+]
+`,
+		},
+		{
+			input: "C3018180",
+			d:     dumper{render: "int"},
+			want: `[
+  1,
+  128,
+]
+`,
+		},
+		{
+			input: "C20180",
+			d:     dumper{render: "ascii"},
+			want: `[
+  "\x01",
+  "",
+]
+`,
+		},
+		// Concatenated values.
+		{input: "0102C0", want: "01\n\n02\n\n[]\n"},
+		// Non-canonical encodings are flagged.
+		{input: "8105", want: "05  <non-canonical: single byte below 0x80 encoded as string>\n"},
+		{input: "B803616263", want: "\"abc\"  <non-canonical: size 3 uses long form>\n"},
+		{input: "B90003616263", want: "\"abc\"  <non-canonical: size has leading zero bytes>\n"},
+		{input: "F800", want: "[]  <non-canonical: size has leading zero bytes>\n"},
+		{input: "F801C0", want: "[  <non-canonical: size 1 uses long form>\n  [],\n]\n"},
+		{input: "820001", d: dumper{render: "int"}, want: "1  <non-canonical: integer has leading zero bytes>\n"},
+		{
+			input: "C58105B80161",
+			want: `[
+  05,  <non-canonical: single byte below 0x80 encoded as string>
+  "a",  <non-canonical: size 1 uses long form>
+]
+`,
+		},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		d := test.d
+		d.out = &out
+		if d.render == "" {
+			d.render = "auto"
+		}
+		input, _ := hex.DecodeString(test.input)
+		if err := d.dumpAll(input, false); err != nil {
+			t.Errorf("%s: error: %v", test.input, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: wrong output\ngot:\n%s\nwant:\n%s", test.input, out.String(), test.want)
+		}
+	}
+}
+
+func TestDumpInvalid(t *testing.T) {
+	tests := []struct {
+		input string
+		out   string // output before the error
+		err   string
+	}{
+		{input: "C301", err: "at offset 0: value size exceeds available input"},
+		{input: "C3836162", out: "[\n", err: "at offset 1: value size exceeds available input"},
+		{input: "01C2C281", out: "01\n\n[\n", err: "at offset 2: value size exceeds available input"},
+		{input: "B8", err: "at offset 0: unexpected EOF"},
+		{input: "C1F9", out: "[\n", err: "at offset 1: unexpected EOF"},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		d := dumper{out: &out, render: "auto"}
+		input, _ := hex.DecodeString(test.input)
+		err := d.dumpAll(input, false)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s: got error %v, want %s", test.input, err, test.err)
+		}
+		if out.String() != test.out {
+			t.Errorf("%s: wrong output %q, want %q", test.input, out.String(), test.out)
+		}
+	}
+}
+
+func TestDumpSingle(t *testing.T) {
+	var out bytes.Buffer
+	d := dumper{out: &out, render: "auto"}
+	if err := d.dumpAll([]byte{0xC1, 0x01, 0x02, 0x03}, true); err != nil {
+		t.Fatal(err)
+	}
+	want := "[\n  01,\n]\n\n2 bytes of trailing data\n"
+	if out.String() != want {
+		t.Fatalf("wrong output %q, want %q", out.String(), want)
+	}
+}
+
+func TestDumpQuery(t *testing.T) {
+	// Two top-level values [1, [2, 3]] and [4, [5, 6]].
+	input, _ := hex.DecodeString("C401C20203C404C20506")
+	tests := []struct {
+		path   string
+		single bool
+		want   string
+		err    string
+	}{
+		{path: "[1][0]", want: "00000003: 02\n00000008: 05\n"},
+		{path: "[1][0]", single: true, want: "00000003: 02\n"},
+		{path: "[1]", single: true, want: "00000002: [\n00000003:   02,\n00000004:   03,\n          ]\n"},
+		// Results of .len are not in the input.
+		{path: "[1].len", want: "          02\n          02\n"},
+		{path: "[2]", err: "value at offset 0: query: [2]: index 2 out of range"},
+		{path: "[x]", err: `query: invalid index "x" at offset 0`},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		d := dumper{out: &out, render: "auto", offsets: true}
+		err := d.dumpQuery(input, test.path, test.single)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: got error %v, want %s", test.path, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: error: %v", test.path, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: wrong output\ngot:\n%s\nwant:\n%s", test.path, out.String(), test.want)
+		}
+	}
+}
+
+func TestOffsetIn(t *testing.T) {
+	b := []byte{1, 2, 3, 4}
+	tests := []struct {
+		sub  []byte
+		want int
+	}{
+		{sub: b, want: 0},
+		{sub: b[2:3], want: 2},
+		{sub: b[3:], want: 3},
+		{sub: b[4:], want: -1},
+		{sub: []byte{3}, want: -1},
+		{sub: nil, want: -1},
+	}
+	for i, test := range tests {
+		if got := offsetIn(b, test.sub); got != test.want {
+			t.Errorf("test %d: got %d, want %d", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Command rlpschema prints the RLP schema of types in package core/types,
+// as computed by rlp.Describe. The schema shows the encoded fields of structs
+// in list order, with their optional and tail flags, and how nil pointers
+// are encoded.
+//
+//	rlpschema Header Log
+//	rlpschema -list
+//
+// Types with encoders generated by rlpgen are shown as custom encoders. Build
+// with -tags norlpgen to see the field layout the generator follows:
+//
+//	go run -tags norlpgen ./cmd/rlpschema Header
+package main
+
+import (
+	"awesomeProject/core/types"
+	"awesomeProject/rlp"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// knownTypes holds the exported types of package core/types.
+var knownTypes = map[string]reflect.Type{
+	"AccessList":   reflect.TypeOf(types.AccessList{}),
+	"AccessTuple":  reflect.TypeOf(types.AccessTuple{}),
+	"Block":        reflect.TypeOf(types.Block{}),
+	"BlockNonce":   reflect.TypeOf(types.BlockNonce{}),
+	"Bloom":        reflect.TypeOf(types.Bloom{}),
+	"Extblock":     reflect.TypeOf(types.Extblock{}),
+	"Header":       reflect.TypeOf(types.Header{}),
+	"Log":          reflect.TypeOf(types.Log{}),
+	"Receipt":      reflect.TypeOf(types.Receipt{}),
+	"Receipts":     reflect.TypeOf(types.Receipts{}),
+	"Transaction":  reflect.TypeOf(types.Transaction{}),
+	"Transactions": reflect.TypeOf(types.Transactions{}),
+	"TxData":       reflect.TypeOf((*types.TxData)(nil)).Elem(),
+	"Withdrawal":   reflect.TypeOf(types.Withdrawal{}),
+	"Withdrawals":  reflect.TypeOf(types.Withdrawals{}),
+}
+
+var listTypes = flag.Bool("list", false, "print the names of all known types")
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[-list] TYPE...")
+		flag.PrintDefaults()
+		fmt.Fprintln(os.Stderr, `
+Prints the RLP schema of the given types of package core/types.`)
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	if *listTypes {
+		for _, name := range sortedNames() {
+			fmt.Println(name)
+		}
+		return
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for i, name := range flag.Args() {
+		typ, ok := knownTypes[name]
+		if !ok {
+			die("unknown type", name)
+		}
+		schema, err := rlp.Describe(typ)
+		if err != nil {
+			die(err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(schema)
+	}
+}
+
+func sortedNames() []string {
+	names := make([]string, 0, len(knownTypes))
+	for name := range knownTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func die(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
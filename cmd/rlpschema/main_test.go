@@ -0,0 +1,48 @@
+package main
+
+import (
+	"awesomeProject/rlp"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// notRLPTypes are the exported types of core/types which are not encoded.
+var notRLPTypes = map[string]bool{
+	"DerivableList": true, // interface used by DeriveSha
+	"TrieHasher":    true, // interface used by DeriveSha
+}
+
+// TestKnownTypes checks that knownTypes contains all exported types of
+// package core/types.
+func TestKnownTypes(t *testing.T) {
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+	pkg, err := imp.ImportFrom("awesomeProject/core/types", ".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || notRLPTypes[name] {
+			continue
+		}
+		if knownTypes[name] == nil {
+			t.Errorf("type %s is missing in knownTypes", name)
+		}
+	}
+	for name := range notRLPTypes {
+		if _, ok := scope.Lookup(name).(*types.TypeName); !ok {
+			t.Errorf("type %s in notRLPTypes does not exist", name)
+		}
+	}
+	for name, typ := range knownTypes {
+		if typ.Name() != name || typ.PkgPath() != pkg.Path() {
+			t.Errorf("knownTypes[%q] is %v", name, typ)
+		}
+		if _, err := rlp.Describe(typ); err != nil {
+			t.Errorf("can't describe %s: %v", name, err)
+		}
+	}
+}
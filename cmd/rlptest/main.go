@@ -0,0 +1,69 @@
+// Command rlptest runs Ethereum RLP conformance test vectors against package rlp.
+//
+// Without arguments, the vectors built into package rlptest are used.
+// Otherwise every argument is a JSON vector file in the format of the
+// ethereum/tests RLPTests directory.
+package main
+
+import (
+	"awesomeProject/rlp/rlptest"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		verbose = flag.Bool("v", false, "print passing tests too")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[-v] [FILE ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	tests, err := loadTests(flag.Args())
+	if err != nil {
+		die(err)
+	}
+	var failed int
+	for _, r := range rlptest.RunAll(tests) {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("FAIL %s\n", r.Name)
+			fmt.Printf("\t%s\n", strings.ReplaceAll(r.Err.Error(), "\n", "\n\t"))
+		} else if *verbose {
+			fmt.Printf("PASS %s\n", r.Name)
+		}
+	}
+	fmt.Printf("%d tests, %d passed, %d failed\n", len(tests), len(tests)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadTests(files []string) (map[string]*rlptest.Test, error) {
+	if len(files) == 0 {
+		return rlptest.Builtin()
+	}
+	all := make(map[string]*rlptest.Test)
+	for _, file := range files {
+		tests, err := rlptest.LoadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		for name, t := range tests {
+			if len(files) > 1 {
+				name = file + "/" + name
+			}
+			all[name] = t
+		}
+	}
+	return all, nil
+}
+
+func die(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
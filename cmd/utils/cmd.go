@@ -1,10 +1,29 @@
 package utils
 
 import (
+	"awesomeProject/core/types"
+	"awesomeProject/rlp"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"syscall"
+)
+
+const (
+	importBatchSize = 2500
+
+	// Limits for decoding blocks from import files. Blocks are nested only a
+	// few levels deep, the element limit is well above the transaction count
+	// of any real block.
+	importMaxDepth = 16
+	importMaxElems = 1 << 16
 )
 
 func Fatalf(format string, args ...interface{}) {
@@ -21,3 +40,149 @@ func Fatalf(format string, args ...interface{}) {
 	fmt.Fprintf(w, "Fatal: "+format+"\n", args...)
 	os.Exit(1)
 }
+
+// InterruptContext returns a context which is canceled when the process
+// receives SIGINT (Ctrl-C) or SIGTERM.
+func InterruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// BlockReader is the part of the blockchain used by ExportChain.
+type BlockReader interface {
+	GetBlockByNumber(number uint64) *types.Block
+}
+
+// BlockInserter is the part of the blockchain used by ImportChain.
+type BlockInserter interface {
+	InsertChain(blocks []*types.Block) (int, error)
+}
+
+// ExportChain writes blocks first to last of chain into the file fn. If the
+// file name ends in ".gz", the output is gzipped. The blocks are written to
+// a temporary file, which replaces fn only when all blocks are written, so
+// canceling ctx leaves no partial output behind.
+func ExportChain(ctx context.Context, chain BlockReader, fn string, first, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	fh, err := os.CreateTemp(filepath.Dir(fn), "."+filepath.Base(fn)+".tmp")
+	if err != nil {
+		return err
+	}
+	if err := exportBlocks(ctx, chain, fh, strings.HasSuffix(fn, ".gz"), first, last); err != nil {
+		fh.Close()
+		os.Remove(fh.Name())
+		return err
+	}
+	if err := fh.Close(); err != nil {
+		os.Remove(fh.Name())
+		return err
+	}
+	return os.Rename(fh.Name(), fn)
+}
+
+// ExportAppendChain is like ExportChain, but appends the blocks to fn if the
+// file exists. When the export fails or ctx is canceled, the file is
+// truncated to its previous size.
+func ExportAppendChain(ctx context.Context, chain BlockReader, fn string, first, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	info, err := fh.Stat()
+	if err != nil {
+		return err
+	}
+	if err := exportBlocks(ctx, chain, fh, strings.HasSuffix(fn, ".gz"), first, last); err != nil {
+		fh.Truncate(info.Size())
+		return err
+	}
+	return fh.Close()
+}
+
+func exportBlocks(ctx context.Context, chain BlockReader, fh *os.File, gz bool, first, last uint64) error {
+	var (
+		writer io.Writer = fh
+		gw     *gzip.Writer
+	)
+	if gz {
+		gw = gzip.NewWriter(fh)
+		writer = gw
+	}
+	for nr := first; ; nr++ {
+		block := chain.GetBlockByNumber(nr)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		if err := rlp.EncodeContext(ctx, writer, block); err != nil {
+			return fmt.Errorf("export failed on #%d: %w", nr, err)
+		}
+		if nr == last {
+			break
+		}
+	}
+	if gw != nil {
+		return gw.Close()
+	}
+	return nil
+}
+
+// ImportChain reads the blocks in file fn and inserts them into chain in
+// batches. Gzipped files are recognized by the ".gz" suffix. Import stops
+// between blocks when ctx is canceled, the blocks of the batch being read are
+// not inserted then.
+func ImportChain(ctx context.Context, chain BlockInserter, fn string) error {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+	stream.SetLimits(rlp.Limits{MaxDepth: importMaxDepth, MaxElems: importMaxElems})
+	stream.SetContext(ctx)
+
+	var (
+		blocks []*types.Block
+		n      int // number of blocks read
+	)
+	for ; ; n++ {
+		var b types.Block
+		err := stream.Decode(&b)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("at block %d: %w", n, err)
+		}
+		blocks = append(blocks, &b)
+		if len(blocks) == importBatchSize {
+			if err := insertBlocks(chain, blocks, n+1-len(blocks)); err != nil {
+				return err
+			}
+			blocks = nil
+		}
+	}
+	return insertBlocks(chain, blocks, n-len(blocks))
+}
+
+// insertBlocks inserts a batch of blocks, first is the position of the batch
+// in the imported file.
+func insertBlocks(chain BlockInserter, blocks []*types.Block, first int) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	if i, err := chain.InsertChain(blocks); err != nil {
+		return fmt.Errorf("invalid block %d: %w", first+i, err)
+	}
+	return nil
+}
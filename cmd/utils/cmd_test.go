@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"awesomeProject/core/types"
+	"awesomeProject/rlp"
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testChain is a BlockReader and BlockInserter holding blocks in memory.
+type testChain struct {
+	blocks   []*types.Block
+	inserted []*types.Block
+	onRead   func(number uint64) // called by GetBlockByNumber
+}
+
+func newTestChain(n int) *testChain {
+	chain := new(testChain)
+	for i := 0; i < n; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Difficulty: big.NewInt(1), GasLimit: uint64(i)}
+		chain.blocks = append(chain.blocks, types.NewBlock(header, nil, nil, nil, nil))
+	}
+	return chain
+}
+
+func (c *testChain) GetBlockByNumber(number uint64) *types.Block {
+	if c.onRead != nil {
+		c.onRead(number)
+	}
+	if number >= uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[number]
+}
+
+func (c *testChain) InsertChain(blocks []*types.Block) (int, error) {
+	c.inserted = append(c.inserted, blocks...)
+	return 0, nil
+}
+
+func encodeBlocks(t *testing.T, blocks []*types.Block) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, b := range blocks {
+		if err := rlp.Encode(&buf, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestExportImport(t *testing.T) {
+	chain := newTestChain(5)
+	for _, name := range []string{"chain.rlp", "chain.rlp.gz"} {
+		fn := filepath.Join(t.TempDir(), name)
+		if err := ExportChain(context.Background(), chain, fn, 0, 4); err != nil {
+			t.Fatalf("%s: export error: %v", name, err)
+		}
+		imported := new(testChain)
+		if err := ImportChain(context.Background(), imported, fn); err != nil {
+			t.Fatalf("%s: import error: %v", name, err)
+		}
+		if got, want := encodeBlocks(t, imported.inserted), encodeBlocks(t, chain.blocks); !bytes.Equal(got, want) {
+			t.Errorf("%s: imported blocks differ from exported ones", name)
+		}
+	}
+}
+
+func TestExportAppend(t *testing.T) {
+	chain := newTestChain(5)
+	fn := filepath.Join(t.TempDir(), "chain.rlp")
+	if err := ExportAppendChain(context.Background(), chain, fn, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportAppendChain(context.Background(), chain, fn, 2, 4); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, encodeBlocks(t, chain.blocks)) {
+		t.Fatal("wrong file content")
+	}
+}
+
+func TestExportCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chain := newTestChain(5)
+	chain.onRead = func(number uint64) {
+		if number == 2 {
+			cancel()
+		}
+	}
+	dir := t.TempDir()
+
+	// ExportChain leaves no file behind.
+	fn := filepath.Join(dir, "chain.rlp")
+	err := ExportChain(ctx, chain, fn, 0, 4)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Fatalf("files left behind: %v", entries)
+	}
+
+	// ExportAppendChain restores the previous content.
+	prev := []byte("previous content")
+	if err := os.WriteFile(fn, prev, 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = ExportAppendChain(ctx, chain, fn, 0, 4)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("append: got error %v, want %v", err, context.Canceled)
+	}
+	if content, _ := os.ReadFile(fn); !bytes.Equal(content, prev) {
+		t.Fatalf("append: file content %q, want %q", content, prev)
+	}
+}
+
+func TestImportCanceled(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "chain.rlp")
+	if err := ExportChain(context.Background(), newTestChain(5), fn, 0, 4); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	imported := new(testChain)
+	err := ImportChain(ctx, imported, fn)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if len(imported.inserted) > 0 {
+		t.Fatalf("%d blocks inserted", len(imported.inserted))
+	}
+}
+
+func TestImportLimits(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)}
+	txs := make([]rlp.RawValue, importMaxElems+1)
+	for i := range txs {
+		txs[i] = rlp.EmptyList
+	}
+	enc, err := rlp.EncodeToBytes([]interface{}{header, txs, []interface{}{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(t.TempDir(), "chain.rlp")
+	if err := os.WriteFile(fn, enc, 0644); err != nil {
+		t.Fatal(err)
+	}
+	imported := new(testChain)
+	err = ImportChain(context.Background(), imported, fn)
+	if !errors.Is(err, rlp.ErrTooManyElems) {
+		t.Fatalf("got error %v, want %v", err, rlp.ErrTooManyElems)
+	}
+	if len(imported.inserted) > 0 {
+		t.Fatalf("%d blocks inserted", len(imported.inserted))
+	}
+}
@@ -0,0 +1,120 @@
+package u256
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Hex returns z as a 0x-prefixed hexadecimal number without leading zeros.
+func (z *Int) Hex() string {
+	return string(z.appendHex(make([]byte, 0, 66)))
+}
+
+func (z *Int) appendHex(b []byte) []byte {
+	const digits = "0123456789abcdef"
+	b = append(b, "0x"...)
+	n := z.BitLen()
+	if n == 0 {
+		return append(b, '0')
+	}
+	for i := (n - 1) / 4; i >= 0; i-- {
+		d := (z[i/16] >> (uint(i%16) * 4)) & 0xf
+		b = append(b, digits[d])
+	}
+	return b
+}
+
+// Dec returns z as a decimal number.
+func (z *Int) Dec() string {
+	if z.IsUint64() {
+		return strconv.FormatUint(z[0], 10)
+	}
+	// Divide by 10^19, the largest power of ten that fits a uint64, and
+	// print the remainders from the least significant end.
+	const (
+		chunk       = 10000000000000000000
+		chunkDigits = 19
+	)
+	var (
+		buf [78]byte // 2^256 has 78 decimal digits
+		pos = len(buf)
+		x   = *z
+		rem uint64
+	)
+	for !x.IsUint64() {
+		x, rem = divUint64(&x, chunk)
+		s := strconv.FormatUint(rem, 10)
+		pos -= chunkDigits
+		for i := 0; i < chunkDigits-len(s); i++ {
+			buf[pos+i] = '0'
+		}
+		copy(buf[pos+chunkDigits-len(s):], s)
+	}
+	s := strconv.FormatUint(x[0], 10)
+	pos -= len(s)
+	copy(buf[pos:], s)
+	return string(buf[pos:])
+}
+
+// String returns z as a decimal number.
+func (z *Int) String() string {
+	return z.Dec()
+}
+
+// Format implements fmt.Formatter. The verbs %d, %s and %v print decimal
+// numbers, %x and %X hexadecimal numbers without prefix.
+func (z *Int) Format(s fmt.State, ch rune) {
+	switch ch {
+	case 'x', 'X':
+		h := z.Hex()[2:]
+		if ch == 'X' {
+			h = strings.ToUpper(h)
+		}
+		fmt.Fprint(s, h)
+	default:
+		fmt.Fprint(s, z.Dec())
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler. The value is encoded as
+// a 0x-prefixed hexadecimal number.
+func (z *Int) MarshalText() ([]byte, error) {
+	return z.appendHex(nil), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts
+// 0x-prefixed hexadecimal and decimal numbers.
+func (z *Int) UnmarshalText(input []byte) error {
+	s := string(input)
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return z.SetFromHex(s)
+	}
+	return z.SetFromDecimal(s)
+}
+
+// MarshalJSON implements json.Marshaler. The value is encoded as a JSON
+// string containing a 0x-prefixed hexadecimal number.
+func (z *Int) MarshalJSON() ([]byte, error) {
+	b := append(make([]byte, 0, 68), '"')
+	b = z.appendHex(b)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides strings in the formats
+// accepted by UnmarshalText, plain JSON numbers are accepted as well. A JSON
+// null leaves z unchanged, like for the built-in types.
+func (z *Int) UnmarshalJSON(input []byte) error {
+	if string(input) == "null" {
+		return nil
+	}
+	if len(input) > 0 && input[0] == '"' {
+		var s string
+		if err := json.Unmarshal(input, &s); err != nil {
+			return err
+		}
+		return z.UnmarshalText([]byte(s))
+	}
+	return z.SetFromDecimal(string(input))
+}
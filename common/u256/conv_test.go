@@ -0,0 +1,168 @@
+package u256
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestStringConversion(t *testing.T) {
+	for _, x := range testOperands() {
+		z := toInt(x)
+		if got, want := z.Dec(), x.Text(10); got != want {
+			t.Errorf("Dec(%#x) = %s, want %s", x, got, want)
+		}
+		if got, want := z.String(), x.Text(10); got != want {
+			t.Errorf("String(%#x) = %s, want %s", x, got, want)
+		}
+		if got, want := z.Hex(), "0x"+x.Text(16); got != want {
+			t.Errorf("Hex(%#x) = %s, want %s", x, got, want)
+		}
+		if got, err := FromDecimal(x.Text(10)); err != nil || !got.Eq(z) {
+			t.Errorf("FromDecimal(%s) = %v, %v", x.Text(10), got, err)
+		}
+		if got, err := FromHex("0x" + x.Text(16)); err != nil || !got.Eq(z) {
+			t.Errorf("FromHex(0x%s) = %v, %v", x.Text(16), got, err)
+		}
+	}
+}
+
+func TestSetFromHex(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // decimal
+		err   error
+	}{
+		{input: "0x0", want: "0"},
+		{input: "0X1f", want: "31"},
+		{input: "0x00000001", want: "1"},
+		{input: "0xDEADbeef", want: "3735928559"},
+		{input: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", want: tt256m1.Text(10)},
+		{input: "0x0000ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", want: tt256m1.Text(10)},
+		{input: "0x10000000000000000000000000000000000000000000000000000000000000000", err: ErrOverflow},
+		{input: "", err: ErrEmptyString},
+		{input: "0x", err: ErrEmptyString},
+		{input: "1f", err: ErrMissingPrefix},
+		{input: "0", err: ErrMissingPrefix},
+		{input: "0xg", err: ErrSyntax},
+		{input: "0x-1", err: ErrSyntax},
+	}
+	for _, test := range tests {
+		var z Int
+		err := z.SetFromHex(test.input)
+		if err != test.err {
+			t.Errorf("SetFromHex(%q): got error %v, want %v", test.input, err, test.err)
+			continue
+		}
+		if err == nil && z.Dec() != test.want {
+			t.Errorf("SetFromHex(%q) = %v, want %s", test.input, &z, test.want)
+		}
+	}
+}
+
+func TestSetFromDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // decimal
+		err   error
+	}{
+		{input: "0", want: "0"},
+		{input: "007", want: "7"},
+		{input: "18446744073709551615", want: "18446744073709551615"},
+		{input: "18446744073709551616", want: "18446744073709551616"},
+		{input: tt256m1.Text(10), want: tt256m1.Text(10)},
+		{input: tt256.Text(10), err: ErrOverflow},
+		{input: tt256m1.Text(10) + "0", err: ErrOverflow},
+		{input: "", err: ErrEmptyString},
+		{input: "-1", err: ErrSyntax},
+		{input: "1.5", err: ErrSyntax},
+		{input: "0x10", err: ErrSyntax},
+	}
+	for _, test := range tests {
+		var z Int
+		err := z.SetFromDecimal(test.input)
+		if err != test.err {
+			t.Errorf("SetFromDecimal(%q): got error %v, want %v", test.input, err, test.err)
+			continue
+		}
+		if err == nil && z.Dec() != test.want {
+			t.Errorf("SetFromDecimal(%q) = %v, want %s", test.input, &z, test.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	z := NewInt(3054)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%d", "3054"},
+		{"%s", "3054"},
+		{"%v", "3054"},
+		{"%x", "bee"},
+		{"%X", "BEE"},
+	}
+	for _, test := range tests {
+		if got := fmt.Sprintf(test.format, z); got != test.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", test.format, got, test.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	z := toInt(bigOf("1234567890abcdef1234567890abcdef"))
+	enc, err := json.Marshal(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"0x1234567890abcdef1234567890abcdef"`; string(enc) != want {
+		t.Fatalf("wrong encoding %s, want %s", enc, want)
+	}
+
+	tests := []struct {
+		input string
+		want  *big.Int
+		err   bool
+	}{
+		{input: string(enc), want: z.ToBig()},
+		{input: `"0x0"`, want: big.NewInt(0)},
+		{input: `"255"`, want: big.NewInt(255)},
+		{input: `255`, want: big.NewInt(255)},
+		{input: tt256m1.Text(10), want: tt256m1},
+		{input: tt256.Text(10), err: true},
+		{input: `"0x"`, err: true},
+		{input: `-1`, err: true},
+		{input: `1e3`, err: true},
+		{input: `true`, err: true},
+	}
+	for _, test := range tests {
+		var dec Int
+		err := json.Unmarshal([]byte(test.input), &dec)
+		if (err != nil) != test.err {
+			t.Errorf("input %s: got error %v", test.input, err)
+			continue
+		}
+		if err == nil && dec.ToBig().Cmp(test.want) != 0 {
+			t.Errorf("input %s: got %v, want %v", test.input, &dec, test.want)
+		}
+	}
+}
+
+func TestJSONNull(t *testing.T) {
+	var v struct {
+		A *Int
+		B Int
+	}
+	v.B.SetUint64(7)
+	if err := json.Unmarshal([]byte(`{"A":null,"B":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != nil {
+		t.Errorf("A = %v, want nil", v.A)
+	}
+	if !v.B.Eq(NewInt(7)) {
+		t.Errorf("B = %v, want 7 (unchanged)", &v.B)
+	}
+}
@@ -0,0 +1,443 @@
+// Package u256 implements 256-bit unsigned integers with fixed size.
+//
+// Unlike big.Int, an Int needs no heap allocation: it is an array of four
+// 64-bit words and can be used by value. All arithmetic wraps around modulo
+// 2^256, the *Overflow variants of the operations report when this happens.
+package u256
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// Int is a 256-bit unsigned integer. The words are stored in little-endian
+// order, i.e. z[0] holds the least significant 64 bits. The zero value is 0.
+type Int [4]uint64
+
+var (
+	ErrEmptyString   = errors.New("u256: empty string")
+	ErrMissingPrefix = errors.New("u256: hex string without 0x prefix")
+	ErrSyntax        = errors.New("u256: invalid syntax")
+	ErrOverflow      = errors.New("u256: value larger than 256 bits")
+)
+
+// NewInt returns a new Int set to v.
+func NewInt(v uint64) *Int {
+	return &Int{v}
+}
+
+// FromBig converts b to an Int. It reports whether b is negative or larger
+// than 256 bits, the result is b modulo 2^256 in that case.
+func FromBig(b *big.Int) (*Int, bool) {
+	z := new(Int)
+	overflow := z.SetFromBig(b)
+	return z, overflow
+}
+
+// MustFromBig is like FromBig, but panics on overflow.
+func MustFromBig(b *big.Int) *Int {
+	z, overflow := FromBig(b)
+	if overflow {
+		panic("u256: big.Int does not fit")
+	}
+	return z
+}
+
+// FromHex parses a 0x-prefixed hexadecimal number.
+func FromHex(s string) (*Int, error) {
+	z := new(Int)
+	if err := z.SetFromHex(s); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// FromDecimal parses a decimal number.
+func FromDecimal(s string) (*Int, error) {
+	z := new(Int)
+	if err := z.SetFromDecimal(s); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// Clear sets z to 0.
+func (z *Int) Clear() *Int {
+	*z = Int{}
+	return z
+}
+
+// Set sets z to x.
+func (z *Int) Set(x *Int) *Int {
+	*z = *x
+	return z
+}
+
+// SetUint64 sets z to x.
+func (z *Int) SetUint64(x uint64) *Int {
+	*z = Int{x}
+	return z
+}
+
+// SetBytes interprets b as a big-endian unsigned integer and sets z to that
+// value. If b is longer than 32 bytes, only the last 32 bytes are used.
+func (z *Int) SetBytes(b []byte) *Int {
+	if len(b) > 32 {
+		b = b[len(b)-32:]
+	}
+	*z = Int{}
+	for i, c := range b {
+		shift := uint(len(b)-1-i) * 8
+		z[shift/64] |= uint64(c) << (shift % 64)
+	}
+	return z
+}
+
+// SetFromBig sets z to b. It reports whether b is negative or larger than
+// 256 bits, z is set to b modulo 2^256 in that case.
+func (z *Int) SetFromBig(b *big.Int) bool {
+	*z = Int{}
+	words := b.Bits()
+	overflow := len(words)*bits.UintSize > 256
+	if bits.UintSize == 64 {
+		for i := 0; i < len(words) && i < 4; i++ {
+			z[i] = uint64(words[i])
+		}
+	} else {
+		for i := 0; i < len(words) && i < 8; i++ {
+			z[i/2] |= uint64(words[i]) << (32 * (i % 2))
+		}
+	}
+	if b.Sign() == -1 {
+		z.Neg(z)
+		overflow = true
+	}
+	return overflow
+}
+
+// SetFromHex sets z to the value of the 0x-prefixed hexadecimal number s.
+func (z *Int) SetFromHex(s string) error {
+	if len(s) < 2 || s[0] != '0' || (s[1] != 'x' && s[1] != 'X') {
+		if s == "" {
+			return ErrEmptyString
+		}
+		return ErrMissingPrefix
+	}
+	s = s[2:]
+	if s == "" {
+		return ErrEmptyString
+	}
+	var x Int
+	for i := 0; i < len(s); i++ {
+		d := hexDigit(s[i])
+		if d < 0 {
+			return ErrSyntax
+		}
+		if x[3]>>60 != 0 {
+			return ErrOverflow
+		}
+		x.lsh4()
+		x[0] |= uint64(d)
+	}
+	*z = x
+	return nil
+}
+
+func hexDigit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
+
+// lsh4 shifts z left by four bits.
+func (z *Int) lsh4() {
+	z[3] = z[3]<<4 | z[2]>>60
+	z[2] = z[2]<<4 | z[1]>>60
+	z[1] = z[1]<<4 | z[0]>>60
+	z[0] <<= 4
+}
+
+// SetFromDecimal sets z to the value of the decimal number s.
+func (z *Int) SetFromDecimal(s string) error {
+	if s == "" {
+		return ErrEmptyString
+	}
+	var x, ten Int
+	ten.SetUint64(10)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return ErrSyntax
+		}
+		if _, overflow := x.MulOverflow(&x, &ten); overflow {
+			return ErrOverflow
+		}
+		if _, overflow := x.AddOverflow(&x, NewInt(uint64(c-'0'))); overflow {
+			return ErrOverflow
+		}
+	}
+	*z = x
+	return nil
+}
+
+// Uint64 returns the lower 64 bits of z.
+func (z *Int) Uint64() uint64 {
+	return z[0]
+}
+
+// IsUint64 reports whether z can be represented as a uint64.
+func (z *Int) IsUint64() bool {
+	return z[1]|z[2]|z[3] == 0
+}
+
+// IsZero reports whether z is 0.
+func (z *Int) IsZero() bool {
+	return z[0]|z[1]|z[2]|z[3] == 0
+}
+
+// Eq reports whether z == x.
+func (z *Int) Eq(x *Int) bool {
+	return *z == *x
+}
+
+// Cmp compares z and x and returns -1, 0 or +1.
+func (z *Int) Cmp(x *Int) int {
+	for i := 3; i >= 0; i-- {
+		switch {
+		case z[i] < x[i]:
+			return -1
+		case z[i] > x[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Lt reports whether z < x.
+func (z *Int) Lt(x *Int) bool {
+	return z.Cmp(x) < 0
+}
+
+// Gt reports whether z > x.
+func (z *Int) Gt(x *Int) bool {
+	return z.Cmp(x) > 0
+}
+
+// BitLen returns the number of bits required to represent z.
+func (z *Int) BitLen() int {
+	for i := 3; i >= 0; i-- {
+		if z[i] != 0 {
+			return i*64 + bits.Len64(z[i])
+		}
+	}
+	return 0
+}
+
+// ByteLen returns the number of bytes required to represent z.
+func (z *Int) ByteLen() int {
+	return (z.BitLen() + 7) / 8
+}
+
+// Bytes32 returns z as a 32-byte big-endian array.
+func (z *Int) Bytes32() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		w := z[3-i]
+		for j := 0; j < 8; j++ {
+			b[i*8+j] = byte(w >> (56 - 8*j))
+		}
+	}
+	return b
+}
+
+// Bytes returns z as a big-endian byte slice without leading zero bytes.
+func (z *Int) Bytes() []byte {
+	b := z.Bytes32()
+	return b[32-z.ByteLen():]
+}
+
+// ToBig returns z as a big.Int.
+func (z *Int) ToBig() *big.Int {
+	b := z.Bytes32()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// Add sets z to x + y modulo 2^256.
+func (z *Int) Add(x, y *Int) *Int {
+	z.AddOverflow(x, y)
+	return z
+}
+
+// AddOverflow sets z to x + y modulo 2^256 and reports whether overflow occurred.
+func (z *Int) AddOverflow(x, y *Int) (*Int, bool) {
+	var carry uint64
+	z[0], carry = bits.Add64(x[0], y[0], 0)
+	z[1], carry = bits.Add64(x[1], y[1], carry)
+	z[2], carry = bits.Add64(x[2], y[2], carry)
+	z[3], carry = bits.Add64(x[3], y[3], carry)
+	return z, carry != 0
+}
+
+// Sub sets z to x - y modulo 2^256.
+func (z *Int) Sub(x, y *Int) *Int {
+	z.SubOverflow(x, y)
+	return z
+}
+
+// SubOverflow sets z to x - y modulo 2^256 and reports whether underflow occurred.
+func (z *Int) SubOverflow(x, y *Int) (*Int, bool) {
+	var borrow uint64
+	z[0], borrow = bits.Sub64(x[0], y[0], 0)
+	z[1], borrow = bits.Sub64(x[1], y[1], borrow)
+	z[2], borrow = bits.Sub64(x[2], y[2], borrow)
+	z[3], borrow = bits.Sub64(x[3], y[3], borrow)
+	return z, borrow != 0
+}
+
+// Neg sets z to -x modulo 2^256.
+func (z *Int) Neg(x *Int) *Int {
+	return z.Sub(&Int{}, x)
+}
+
+// Mul sets z to x * y modulo 2^256.
+func (z *Int) Mul(x, y *Int) *Int {
+	z.MulOverflow(x, y)
+	return z
+}
+
+// MulOverflow sets z to x * y modulo 2^256 and reports whether overflow occurred.
+func (z *Int) MulOverflow(x, y *Int) (*Int, bool) {
+	var p [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, p[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			p[i+j] = lo
+			carry = hi
+		}
+		p[i+4] = carry
+	}
+	copy(z[:], p[:4])
+	return z, p[4]|p[5]|p[6]|p[7] != 0
+}
+
+// Div sets z to x / y. If y is 0, z is set to 0.
+func (z *Int) Div(x, y *Int) *Int {
+	q, _ := divMod(x, y)
+	*z = q
+	return z
+}
+
+// Mod sets z to x % y. If y is 0, z is set to 0.
+func (z *Int) Mod(x, y *Int) *Int {
+	_, r := divMod(x, y)
+	*z = r
+	return z
+}
+
+// divMod computes quotient and remainder of x / y.
+func divMod(x, y *Int) (q, r Int) {
+	if y.IsZero() || x.Lt(y) {
+		if !y.IsZero() {
+			r = *x
+		}
+		return q, r
+	}
+	if x.IsUint64() {
+		return Int{x[0] / y[0]}, Int{x[0] % y[0]}
+	}
+	if y.IsUint64() {
+		var rem uint64
+		q, rem = divUint64(x, y[0])
+		return q, Int{rem}
+	}
+	// Shift-subtract long division. The divisor has at least 65 bits here,
+	// so the loop runs fewer than 192 times.
+	for i := x.BitLen() - 1; i >= 0; i-- {
+		r.Lsh(&r, 1)
+		r[0] |= (x[i/64] >> (uint(i) % 64)) & 1
+		if !r.Lt(y) {
+			r.Sub(&r, y)
+			q[i/64] |= 1 << (uint(i) % 64)
+		}
+	}
+	return q, r
+}
+
+// divUint64 divides x by the non-zero d.
+func divUint64(x *Int, d uint64) (q Int, rem uint64) {
+	for i := 3; i >= 0; i-- {
+		q[i], rem = bits.Div64(rem, x[i], d)
+	}
+	return q, rem
+}
+
+// Lsh sets z to x << n.
+func (z *Int) Lsh(x *Int, n uint) *Int {
+	if n >= 256 {
+		return z.Clear()
+	}
+	var r Int
+	words, shift := n/64, n%64
+	for i := 3; i >= int(words); i-- {
+		r[i] = x[i-int(words)] << shift
+		if shift > 0 && i-int(words)-1 >= 0 {
+			r[i] |= x[i-int(words)-1] >> (64 - shift)
+		}
+	}
+	*z = r
+	return z
+}
+
+// Rsh sets z to x >> n.
+func (z *Int) Rsh(x *Int, n uint) *Int {
+	if n >= 256 {
+		return z.Clear()
+	}
+	var r Int
+	words, shift := n/64, n%64
+	for i := 0; i < 4-int(words); i++ {
+		r[i] = x[i+int(words)] >> shift
+		if shift > 0 && i+int(words)+1 < 4 {
+			r[i] |= x[i+int(words)+1] << (64 - shift)
+		}
+	}
+	*z = r
+	return z
+}
+
+// And sets z to x & y.
+func (z *Int) And(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z to x | y.
+func (z *Int) Or(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z to x ^ y.
+func (z *Int) Xor(x, y *Int) *Int {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
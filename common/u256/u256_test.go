@@ -0,0 +1,198 @@
+package u256
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+var (
+	tt256   = new(big.Int).Lsh(big.NewInt(1), 256)
+	tt256m1 = new(big.Int).Sub(tt256, big.NewInt(1))
+)
+
+// bigOf parses a hexadecimal number without prefix.
+func bigOf(s string) *big.Int {
+	b, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex number " + s)
+	}
+	return b
+}
+
+// edgeValues are the operands used in addition to random ones.
+var edgeValues = []*big.Int{
+	big.NewInt(0),
+	big.NewInt(1),
+	big.NewInt(2),
+	big.NewInt(10),
+	bigOf("ffffffffffffffff"),
+	bigOf("10000000000000000"),
+	bigOf("ffffffffffffffffffffffffffffffff"),
+	bigOf("100000000000000000000000000000000"),
+	bigOf("8000000000000000000000000000000000000000000000000000000000000000"),
+	bigOf("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"),
+	tt256m1,
+}
+
+// testOperands returns the edge values followed by random values of random
+// bit length.
+func testOperands() []*big.Int {
+	rng := rand.New(rand.NewSource(1))
+	ops := append([]*big.Int{}, edgeValues...)
+	for i := 0; i < 40; i++ {
+		b := new(big.Int).Rand(rng, tt256)
+		ops = append(ops, b.Rsh(b, uint(rng.Intn(256))))
+	}
+	return ops
+}
+
+func toInt(b *big.Int) *Int {
+	return MustFromBig(b)
+}
+
+// wrap reduces b modulo 2^256.
+func wrap(b *big.Int) *big.Int {
+	return b.Mod(b, tt256)
+}
+
+func checkBig(t *testing.T, op string, x, y *big.Int, got *Int, want *big.Int) {
+	t.Helper()
+	if got.ToBig().Cmp(want) != 0 {
+		t.Errorf("%s(%#x, %#x) = %#x, want %#x", op, x, y, got.ToBig(), want)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	ops := testOperands()
+	for _, x := range ops {
+		for _, y := range ops {
+			checkBig(t, "Add", x, y, new(Int).Add(toInt(x), toInt(y)), wrap(new(big.Int).Add(x, y)))
+			checkBig(t, "Sub", x, y, new(Int).Sub(toInt(x), toInt(y)), wrap(new(big.Int).Sub(x, y)))
+			checkBig(t, "Mul", x, y, new(Int).Mul(toInt(x), toInt(y)), wrap(new(big.Int).Mul(x, y)))
+			if y.Sign() == 0 {
+				checkBig(t, "Div", x, y, new(Int).Div(toInt(x), toInt(y)), new(big.Int))
+				checkBig(t, "Mod", x, y, new(Int).Mod(toInt(x), toInt(y)), new(big.Int))
+			} else {
+				checkBig(t, "Div", x, y, new(Int).Div(toInt(x), toInt(y)), new(big.Int).Div(x, y))
+				checkBig(t, "Mod", x, y, new(Int).Mod(toInt(x), toInt(y)), new(big.Int).Mod(x, y))
+			}
+			checkBig(t, "And", x, y, new(Int).And(toInt(x), toInt(y)), new(big.Int).And(x, y))
+			checkBig(t, "Or", x, y, new(Int).Or(toInt(x), toInt(y)), new(big.Int).Or(x, y))
+			checkBig(t, "Xor", x, y, new(Int).Xor(toInt(x), toInt(y)), new(big.Int).Xor(x, y))
+			if got, want := toInt(x).Cmp(toInt(y)), x.Cmp(y); got != want {
+				t.Errorf("Cmp(%#x, %#x) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	ops := testOperands()
+	for _, x := range ops {
+		for _, y := range ops {
+			sum := new(big.Int).Add(x, y)
+			if _, overflow := new(Int).AddOverflow(toInt(x), toInt(y)); overflow != (sum.Cmp(tt256m1) > 0) {
+				t.Errorf("AddOverflow(%#x, %#x) overflow = %t", x, y, overflow)
+			}
+			if _, overflow := new(Int).SubOverflow(toInt(x), toInt(y)); overflow != (x.Cmp(y) < 0) {
+				t.Errorf("SubOverflow(%#x, %#x) overflow = %t", x, y, overflow)
+			}
+			prod := new(big.Int).Mul(x, y)
+			if _, overflow := new(Int).MulOverflow(toInt(x), toInt(y)); overflow != (prod.Cmp(tt256m1) > 0) {
+				t.Errorf("MulOverflow(%#x, %#x) overflow = %t", x, y, overflow)
+			}
+		}
+	}
+}
+
+func TestNeg(t *testing.T) {
+	for _, x := range testOperands() {
+		checkBig(t, "Neg", x, nil, new(Int).Neg(toInt(x)), wrap(new(big.Int).Neg(x)))
+	}
+}
+
+func TestShift(t *testing.T) {
+	shifts := []uint{0, 1, 4, 63, 64, 65, 127, 128, 129, 192, 255, 256, 300}
+	for _, x := range testOperands() {
+		for _, n := range shifts {
+			if got, want := new(Int).Lsh(toInt(x), n), wrap(new(big.Int).Lsh(x, n)); got.ToBig().Cmp(want) != 0 {
+				t.Errorf("Lsh(%#x, %d) = %#x, want %#x", x, n, got.ToBig(), want)
+			}
+			if got, want := new(Int).Rsh(toInt(x), n), new(big.Int).Rsh(x, n); got.ToBig().Cmp(want) != 0 {
+				t.Errorf("Rsh(%#x, %d) = %#x, want %#x", x, n, got.ToBig(), want)
+			}
+		}
+	}
+}
+
+func TestAliasing(t *testing.T) {
+	x := toInt(bigOf("123456789abcdef0123456789abcdef0123456789abcdef"))
+	y := toInt(bigOf("fedcba9876543210fedcba9876543210"))
+	want := new(Int).Mul(x, y)
+	if got := x.Mul(x, y); !got.Eq(want) {
+		t.Errorf("Mul with z == x: got %v, want %v", got, want)
+	}
+	want = new(Int).Div(x, y)
+	if got := y.Div(x, y); !got.Eq(want) {
+		t.Errorf("Div with z == y: got %v, want %v", got, want)
+	}
+}
+
+func TestBigConversion(t *testing.T) {
+	for _, x := range testOperands() {
+		z, overflow := FromBig(x)
+		if overflow {
+			t.Errorf("FromBig(%#x) reports overflow", x)
+		}
+		if z.ToBig().Cmp(x) != 0 {
+			t.Errorf("FromBig(%#x).ToBig() = %#x", x, z.ToBig())
+		}
+		if got := z.BitLen(); got != x.BitLen() {
+			t.Errorf("BitLen(%#x) = %d, want %d", x, got, x.BitLen())
+		}
+		if got := new(Int).SetBytes(x.Bytes()); !got.Eq(z) {
+			t.Errorf("SetBytes(%x) = %v, want %v", x.Bytes(), got, z)
+		}
+		if got := z.Bytes(); new(big.Int).SetBytes(got).Cmp(x) != 0 || len(got) != z.ByteLen() {
+			t.Errorf("Bytes(%#x) = %x", x, got)
+		}
+		if z.IsUint64() != x.IsUint64() {
+			t.Errorf("IsUint64(%#x) = %t", x, z.IsUint64())
+		}
+	}
+
+	tests := []struct {
+		input    *big.Int
+		want     *big.Int
+		overflow bool
+	}{
+		{input: tt256, want: big.NewInt(0), overflow: true},
+		{input: new(big.Int).Add(tt256, big.NewInt(5)), want: big.NewInt(5), overflow: true},
+		{input: big.NewInt(-1), want: tt256m1, overflow: true},
+		{input: big.NewInt(-256), want: wrap(big.NewInt(-256)), overflow: true},
+	}
+	for _, test := range tests {
+		z, overflow := FromBig(test.input)
+		if overflow != test.overflow || z.ToBig().Cmp(test.want) != 0 {
+			t.Errorf("FromBig(%v) = %#x, %t, want %#x, %t", test.input, z.ToBig(), overflow, test.want, test.overflow)
+		}
+	}
+}
+
+func TestSetBytesLong(t *testing.T) {
+	b := make([]byte, 40)
+	b[0] = 0xff // cut off
+	b[39] = 1
+	if got := new(Int).SetBytes(b); !got.Eq(NewInt(1)) {
+		t.Errorf("got %v, want 1", got)
+	}
+}
+
+func TestBytes32(t *testing.T) {
+	z := toInt(bigOf("0102"))
+	b := z.Bytes32()
+	if b[30] != 1 || b[31] != 2 || new(big.Int).SetBytes(b[:]).Cmp(z.ToBig()) != 0 {
+		t.Errorf("wrong Bytes32 %x", b)
+	}
+}
@@ -11,6 +11,9 @@ import (
 
 type BlockNonce [8]byte
 
+//go:generate go run ../../rlp/rlpgen -type Header -out gen_header_rlp.go
+
+// Header represents a block header in the Ethereum blockchain.
 type Header struct {
 	ParentHash  common.Hash    `json:"parentHash"       gencodec:"required"`
 	UncleHash   common.Hash    `json:"sha3Uncles"       gencodec:"required"`
@@ -70,6 +73,9 @@ var (
 	EmptyUncleHash = rlpHash([]*Header(nil))
 )
 
+//go:generate go run ../../rlp/rlpgen -type extblock -out gen_extblock_rlp.go
+
+// extblock is the "external" block encoding used for the eth protocol, etc.
 type extblock struct {
 	Header      *Header
 	Txs         []*Transaction
@@ -92,13 +98,40 @@ func Newextblock(block *Block) *Extblock {
 	}
 }
 func (b *Block) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, extblock{
+	return rlp.Encode(w, &extblock{
 		Header:      b.header,
 		Txs:         b.transactions,
 		Uncles:      b.uncles,
 		Withdrawals: b.withdrawals,
 	})
 }
+
+// DecodeRLP decodes a block from the extblock encoding.
+func (b *Block) DecodeRLP(s *rlp.Stream) error {
+	var eb extblock
+	_, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if err := s.Decode(&eb); err != nil {
+		return err
+	}
+	b.header, b.uncles, b.transactions, b.withdrawals = eb.Header, eb.Uncles, eb.Txs, eb.Withdrawals
+	b.size.Store(rlp.ListSize(size))
+	return nil
+}
+
+// Size returns the true RLP encoded storage size of the block, either by encoding
+// and returning it, or returning a previously cached value.
+func (b *Block) Size() uint64 {
+	if size := b.size.Load(); size != nil {
+		return size.(uint64)
+	}
+	c, _ := rlp.EncodedSize(b)
+	b.size.Store(uint64(c))
+	return uint64(c)
+}
+
 func NewBlock(header *Header, txs []*Transaction, uncles []*Header, receipts []*Receipt, hasher TrieHasher) *Block {
 	b := &Block{header: CopyHeader(header)}
 
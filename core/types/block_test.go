@@ -0,0 +1,76 @@
+package types
+
+import (
+	"awesomeProject/rlp"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBlockDecodeErrorPath(t *testing.T) {
+	header := &Header{Number: big.NewInt(0x4321), Difficulty: big.NewInt(1), GasLimit: 5000}
+	enc, err := rlp.EncodeToBytes(NewBlock(header, nil, nil, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Give Header.Number a leading zero byte.
+	number, _ := hex.DecodeString("824321")
+	if bytes.Count(enc, number) != 1 {
+		t.Fatalf("can't locate Header.Number in %x", enc)
+	}
+	corrupt, _ := hex.DecodeString("820043")
+	enc = bytes.Replace(enc, number, corrupt, 1)
+
+	var block Block
+	err = rlp.DecodeBytes(enc, &block)
+	if err == nil {
+		t.Fatal("no error for corrupted block")
+	}
+	if want := "decoding into types.Block.Header.Number"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+	if !errors.Is(err, rlp.ErrCanonInt) {
+		t.Errorf("error %v does not wrap ErrCanonInt", err)
+	}
+}
+
+// The reflect* types have the layout of the types with generated encoders,
+// but no methods, so package rlp uses reflection for them.
+type (
+	reflectHeader Header
+	reflectBlock  struct {
+		Header *reflectHeader
+		Txs    []*Transaction
+		Uncles []*reflectHeader
+	}
+)
+
+func TestBlockEncodeErrorPath(t *testing.T) {
+	header := &Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	uncle := &Header{Number: big.NewInt(-1), Difficulty: big.NewInt(1)}
+	block := NewBlock(header, nil, []*Header{uncle}, nil, nil)
+	tests := []struct {
+		val  interface{}
+		want string
+	}{
+		{block, "Block.Uncles[0].Number: rlp: cannot encode negative big.Int"},
+		{uncle, "Header.Number: rlp: cannot encode negative big.Int"},
+		{
+			&reflectBlock{Header: (*reflectHeader)(header), Uncles: []*reflectHeader{(*reflectHeader)(uncle)}},
+			"reflectBlock.Uncles[0].Number: rlp: cannot encode negative big.Int",
+		},
+		{(*reflectHeader)(uncle), "reflectHeader.Number: rlp: cannot encode negative big.Int"},
+	}
+	for _, test := range tests {
+		_, err := rlp.EncodeToBytes(test.val)
+		if err == nil || err.Error() != test.want {
+			t.Errorf("%T: got error %v, want %s", test.val, err, test.want)
+		}
+		if !errors.Is(err, rlp.ErrNegativeBigInt) {
+			t.Errorf("%T: error %v does not wrap ErrNegativeBigInt", test.val, err)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+//go:build !norlpgen
+// +build !norlpgen
+
+package types
+
+import "awesomeProject/common"
+import "awesomeProject/rlp"
+import "io"
+import "strconv"
+
+func (obj *extblock) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	if obj.Header == nil {
+		w.Write(rlp.EmptyList)
+	} else {
+		_tmp1 := w.List()
+		w.WriteBytes(obj.Header.ParentHash[:])
+		w.WriteBytes(obj.Header.UncleHash[:])
+		w.WriteBytes(obj.Header.Coinbase[:])
+		w.WriteBytes(obj.Header.Root[:])
+		w.WriteBytes(obj.Header.TxHash[:])
+		w.WriteBytes(obj.Header.ReceiptHash[:])
+		w.WriteBytes(obj.Header.Bloom[:])
+		if obj.Header.Difficulty == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			if obj.Header.Difficulty.Sign() == -1 {
+				return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Header.Difficulty")
+			}
+			w.WriteBigInt(obj.Header.Difficulty)
+		}
+		if obj.Header.Number == nil {
+			w.Write(rlp.EmptyString)
+		} else {
+			if obj.Header.Number.Sign() == -1 {
+				return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Header.Number")
+			}
+			w.WriteBigInt(obj.Header.Number)
+		}
+		w.WriteUint64(obj.Header.GasLimit)
+		w.WriteUint64(obj.Header.GasUsed)
+		w.WriteUint64(obj.Header.Time)
+		w.WriteBytes(obj.Header.Extra)
+		w.WriteBytes(obj.Header.MixDigest[:])
+		w.WriteBytes(obj.Header.Nonce[:])
+		_tmp2 := obj.Header.BaseFee != nil
+		_tmp3 := obj.Header.WithdrawalsHash != nil
+		if _tmp2 || _tmp3 {
+			if obj.Header.BaseFee == nil {
+				w.Write(rlp.EmptyString)
+			} else {
+				if obj.Header.BaseFee.Sign() == -1 {
+					return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Header.BaseFee")
+				}
+				w.WriteBigInt(obj.Header.BaseFee)
+			}
+		}
+		if _tmp3 {
+			if obj.Header.WithdrawalsHash == nil {
+				w.Write(rlp.EmptyString)
+			} else {
+				w.WriteBytes(obj.Header.WithdrawalsHash[:])
+			}
+		}
+		w.ListEnd(_tmp1)
+	}
+	_tmp4 := w.List()
+	for _tmp5 := range obj.Txs {
+		if obj.Txs[_tmp5] == nil {
+			w.Write(rlp.EmptyList)
+		} else {
+			_tmp6 := w.List()
+			w.ListEnd(_tmp6)
+		}
+	}
+	w.ListEnd(_tmp4)
+	_tmp7 := w.List()
+	for _tmp8 := range obj.Uncles {
+		if obj.Uncles[_tmp8] == nil {
+			w.Write(rlp.EmptyList)
+		} else {
+			_tmp9 := w.List()
+			w.WriteBytes(obj.Uncles[_tmp8].ParentHash[:])
+			w.WriteBytes(obj.Uncles[_tmp8].UncleHash[:])
+			w.WriteBytes(obj.Uncles[_tmp8].Coinbase[:])
+			w.WriteBytes(obj.Uncles[_tmp8].Root[:])
+			w.WriteBytes(obj.Uncles[_tmp8].TxHash[:])
+			w.WriteBytes(obj.Uncles[_tmp8].ReceiptHash[:])
+			w.WriteBytes(obj.Uncles[_tmp8].Bloom[:])
+			if obj.Uncles[_tmp8].Difficulty == nil {
+				w.Write(rlp.EmptyString)
+			} else {
+				if obj.Uncles[_tmp8].Difficulty.Sign() == -1 {
+					return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Uncles["+strconv.Itoa(_tmp8)+"].Difficulty")
+				}
+				w.WriteBigInt(obj.Uncles[_tmp8].Difficulty)
+			}
+			if obj.Uncles[_tmp8].Number == nil {
+				w.Write(rlp.EmptyString)
+			} else {
+				if obj.Uncles[_tmp8].Number.Sign() == -1 {
+					return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Uncles["+strconv.Itoa(_tmp8)+"].Number")
+				}
+				w.WriteBigInt(obj.Uncles[_tmp8].Number)
+			}
+			w.WriteUint64(obj.Uncles[_tmp8].GasLimit)
+			w.WriteUint64(obj.Uncles[_tmp8].GasUsed)
+			w.WriteUint64(obj.Uncles[_tmp8].Time)
+			w.WriteBytes(obj.Uncles[_tmp8].Extra)
+			w.WriteBytes(obj.Uncles[_tmp8].MixDigest[:])
+			w.WriteBytes(obj.Uncles[_tmp8].Nonce[:])
+			_tmp10 := obj.Uncles[_tmp8].BaseFee != nil
+			_tmp11 := obj.Uncles[_tmp8].WithdrawalsHash != nil
+			if _tmp10 || _tmp11 {
+				if obj.Uncles[_tmp8].BaseFee == nil {
+					w.Write(rlp.EmptyString)
+				} else {
+					if obj.Uncles[_tmp8].BaseFee.Sign() == -1 {
+						return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Uncles["+strconv.Itoa(_tmp8)+"].BaseFee")
+					}
+					w.WriteBigInt(obj.Uncles[_tmp8].BaseFee)
+				}
+			}
+			if _tmp11 {
+				if obj.Uncles[_tmp8].WithdrawalsHash == nil {
+					w.Write(rlp.EmptyString)
+				} else {
+					w.WriteBytes(obj.Uncles[_tmp8].WithdrawalsHash[:])
+				}
+			}
+			w.ListEnd(_tmp9)
+		}
+	}
+	w.ListEnd(_tmp7)
+	_tmp12 := obj.Withdrawals != nil
+	if _tmp12 {
+		_tmp13 := w.List()
+		for _tmp14 := range obj.Withdrawals {
+			if obj.Withdrawals[_tmp14] == nil {
+				w.Write(rlp.EmptyList)
+			} else {
+				_tmp15 := w.List()
+				w.WriteUint64(obj.Withdrawals[_tmp14].Index)
+				w.WriteUint64(obj.Withdrawals[_tmp14].Validator)
+				w.WriteBytes(obj.Withdrawals[_tmp14].Address[:])
+				w.WriteUint64(obj.Withdrawals[_tmp14].Amount)
+				w.ListEnd(_tmp15)
+			}
+		}
+		w.ListEnd(_tmp13)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *extblock) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp0 extblock
+	{
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		// Header:
+		var _tmp1 *Header
+		{
+			var _tmp2 Header
+			{
+				if _, err := dec.List(); err != nil {
+					return rlp.WrapDecodeError(err, ".Header")
+				}
+				// ParentHash:
+				var _tmp3 common.Hash
+				if err := dec.ReadBytes(_tmp3[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.ParentHash")
+				}
+				_tmp2.ParentHash = _tmp3
+				// UncleHash:
+				var _tmp4 common.Hash
+				if err := dec.ReadBytes(_tmp4[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.UncleHash")
+				}
+				_tmp2.UncleHash = _tmp4
+				// Coinbase:
+				var _tmp5 common.Address
+				if err := dec.ReadBytes(_tmp5[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Coinbase")
+				}
+				_tmp2.Coinbase = _tmp5
+				// Root:
+				var _tmp6 common.Hash
+				if err := dec.ReadBytes(_tmp6[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Root")
+				}
+				_tmp2.Root = _tmp6
+				// TxHash:
+				var _tmp7 common.Hash
+				if err := dec.ReadBytes(_tmp7[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.TxHash")
+				}
+				_tmp2.TxHash = _tmp7
+				// ReceiptHash:
+				var _tmp8 common.Hash
+				if err := dec.ReadBytes(_tmp8[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.ReceiptHash")
+				}
+				_tmp2.ReceiptHash = _tmp8
+				// Bloom:
+				var _tmp9 Bloom
+				if err := dec.ReadBytes(_tmp9[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Bloom")
+				}
+				_tmp2.Bloom = _tmp9
+				// Difficulty:
+				_tmp10, err := dec.BigInt()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Difficulty")
+				}
+				_tmp2.Difficulty = _tmp10
+				// Number:
+				_tmp11, err := dec.BigInt()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Number")
+				}
+				_tmp2.Number = _tmp11
+				// GasLimit:
+				_tmp12, err := dec.Uint64()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.GasLimit")
+				}
+				_tmp2.GasLimit = _tmp12
+				// GasUsed:
+				_tmp13, err := dec.Uint64()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.GasUsed")
+				}
+				_tmp2.GasUsed = _tmp13
+				// Time:
+				_tmp14, err := dec.Uint64()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Time")
+				}
+				_tmp2.Time = _tmp14
+				// Extra:
+				_tmp15, err := dec.Bytes()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Extra")
+				}
+				_tmp2.Extra = _tmp15
+				// MixDigest:
+				var _tmp16 common.Hash
+				if err := dec.ReadBytes(_tmp16[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.MixDigest")
+				}
+				_tmp2.MixDigest = _tmp16
+				// Nonce:
+				var _tmp17 BlockNonce
+				if err := dec.ReadBytes(_tmp17[:]); err != nil {
+					return rlp.WrapDecodeError(err, ".Header.Nonce")
+				}
+				_tmp2.Nonce = _tmp17
+				if dec.MoreDataInList() {
+					// BaseFee:
+					_tmp18, err := dec.BigInt()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Header.BaseFee")
+					}
+					_tmp2.BaseFee = _tmp18
+				}
+				if dec.MoreDataInList() {
+					// WithdrawalsHash:
+					var _tmp19 *common.Hash
+					{
+						var _tmp20 common.Hash
+						if err := dec.ReadBytes(_tmp20[:]); err != nil {
+							return rlp.WrapDecodeError(err, ".Header.WithdrawalsHash")
+						}
+						_tmp19 = &_tmp20
+					}
+					_tmp2.WithdrawalsHash = _tmp19
+				}
+				if err := dec.ListEnd(); err != nil {
+					return rlp.WrapDecodeError(err, ".Header")
+				}
+			}
+			_tmp1 = &_tmp2
+		}
+		_tmp0.Header = _tmp1
+		// Txs:
+		_tmp21 := []*Transaction{}
+		if _, err := dec.List(); err != nil {
+			return rlp.WrapDecodeError(err, ".Txs")
+		}
+		for dec.MoreDataInList() {
+			var _tmp22 *Transaction
+			{
+				var _tmp23 Transaction
+				{
+					if _, err := dec.List(); err != nil {
+						return rlp.WrapDecodeError(err, ".Txs["+strconv.Itoa(len(_tmp21))+"]")
+					}
+					if err := dec.ListEnd(); err != nil {
+						return rlp.WrapDecodeError(err, ".Txs["+strconv.Itoa(len(_tmp21))+"]")
+					}
+				}
+				_tmp22 = &_tmp23
+			}
+			_tmp21 = append(_tmp21, _tmp22)
+		}
+		if err := dec.ListEnd(); err != nil {
+			return rlp.WrapDecodeError(err, ".Txs")
+		}
+		_tmp0.Txs = _tmp21
+		// Uncles:
+		_tmp24 := []*Header{}
+		if _, err := dec.List(); err != nil {
+			return rlp.WrapDecodeError(err, ".Uncles")
+		}
+		for dec.MoreDataInList() {
+			var _tmp25 *Header
+			{
+				var _tmp26 Header
+				{
+					if _, err := dec.List(); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"]")
+					}
+					// ParentHash:
+					var _tmp27 common.Hash
+					if err := dec.ReadBytes(_tmp27[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].ParentHash")
+					}
+					_tmp26.ParentHash = _tmp27
+					// UncleHash:
+					var _tmp28 common.Hash
+					if err := dec.ReadBytes(_tmp28[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].UncleHash")
+					}
+					_tmp26.UncleHash = _tmp28
+					// Coinbase:
+					var _tmp29 common.Address
+					if err := dec.ReadBytes(_tmp29[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Coinbase")
+					}
+					_tmp26.Coinbase = _tmp29
+					// Root:
+					var _tmp30 common.Hash
+					if err := dec.ReadBytes(_tmp30[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Root")
+					}
+					_tmp26.Root = _tmp30
+					// TxHash:
+					var _tmp31 common.Hash
+					if err := dec.ReadBytes(_tmp31[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].TxHash")
+					}
+					_tmp26.TxHash = _tmp31
+					// ReceiptHash:
+					var _tmp32 common.Hash
+					if err := dec.ReadBytes(_tmp32[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].ReceiptHash")
+					}
+					_tmp26.ReceiptHash = _tmp32
+					// Bloom:
+					var _tmp33 Bloom
+					if err := dec.ReadBytes(_tmp33[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Bloom")
+					}
+					_tmp26.Bloom = _tmp33
+					// Difficulty:
+					_tmp34, err := dec.BigInt()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Difficulty")
+					}
+					_tmp26.Difficulty = _tmp34
+					// Number:
+					_tmp35, err := dec.BigInt()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Number")
+					}
+					_tmp26.Number = _tmp35
+					// GasLimit:
+					_tmp36, err := dec.Uint64()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].GasLimit")
+					}
+					_tmp26.GasLimit = _tmp36
+					// GasUsed:
+					_tmp37, err := dec.Uint64()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].GasUsed")
+					}
+					_tmp26.GasUsed = _tmp37
+					// Time:
+					_tmp38, err := dec.Uint64()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Time")
+					}
+					_tmp26.Time = _tmp38
+					// Extra:
+					_tmp39, err := dec.Bytes()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Extra")
+					}
+					_tmp26.Extra = _tmp39
+					// MixDigest:
+					var _tmp40 common.Hash
+					if err := dec.ReadBytes(_tmp40[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].MixDigest")
+					}
+					_tmp26.MixDigest = _tmp40
+					// Nonce:
+					var _tmp41 BlockNonce
+					if err := dec.ReadBytes(_tmp41[:]); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].Nonce")
+					}
+					_tmp26.Nonce = _tmp41
+					if dec.MoreDataInList() {
+						// BaseFee:
+						_tmp42, err := dec.BigInt()
+						if err != nil {
+							return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].BaseFee")
+						}
+						_tmp26.BaseFee = _tmp42
+					}
+					if dec.MoreDataInList() {
+						// WithdrawalsHash:
+						var _tmp43 *common.Hash
+						{
+							var _tmp44 common.Hash
+							if err := dec.ReadBytes(_tmp44[:]); err != nil {
+								return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"].WithdrawalsHash")
+							}
+							_tmp43 = &_tmp44
+						}
+						_tmp26.WithdrawalsHash = _tmp43
+					}
+					if err := dec.ListEnd(); err != nil {
+						return rlp.WrapDecodeError(err, ".Uncles["+strconv.Itoa(len(_tmp24))+"]")
+					}
+				}
+				_tmp25 = &_tmp26
+			}
+			_tmp24 = append(_tmp24, _tmp25)
+		}
+		if err := dec.ListEnd(); err != nil {
+			return rlp.WrapDecodeError(err, ".Uncles")
+		}
+		_tmp0.Uncles = _tmp24
+		if dec.MoreDataInList() {
+			// Withdrawals:
+			_tmp45 := []*Withdrawal{}
+			if _, err := dec.List(); err != nil {
+				return rlp.WrapDecodeError(err, ".Withdrawals")
+			}
+			for dec.MoreDataInList() {
+				var _tmp46 *Withdrawal
+				{
+					var _tmp47 Withdrawal
+					{
+						if _, err := dec.List(); err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"]")
+						}
+						// Index:
+						_tmp48, err := dec.Uint64()
+						if err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"].Index")
+						}
+						_tmp47.Index = _tmp48
+						// Validator:
+						_tmp49, err := dec.Uint64()
+						if err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"].Validator")
+						}
+						_tmp47.Validator = _tmp49
+						// Address:
+						var _tmp50 common.Address
+						if err := dec.ReadBytes(_tmp50[:]); err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"].Address")
+						}
+						_tmp47.Address = _tmp50
+						// Amount:
+						_tmp51, err := dec.Uint64()
+						if err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"].Amount")
+						}
+						_tmp47.Amount = _tmp51
+						if err := dec.ListEnd(); err != nil {
+							return rlp.WrapDecodeError(err, ".Withdrawals["+strconv.Itoa(len(_tmp45))+"]")
+						}
+					}
+					_tmp46 = &_tmp47
+				}
+				_tmp45 = append(_tmp45, _tmp46)
+			}
+			if err := dec.ListEnd(); err != nil {
+				return rlp.WrapDecodeError(err, ".Withdrawals")
+			}
+			_tmp0.Withdrawals = _tmp45
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	*obj = _tmp0
+	return nil
+}
@@ -0,0 +1,109 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+//go:build !norlpgen
+// +build !norlpgen
+
+package types
+
+import "awesomeProject/common"
+import "awesomeProject/rlp"
+import "io"
+import "strconv"
+
+func (obj *Log) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteBytes(obj.Address[:])
+	_tmp1 := w.List()
+	for _tmp2 := range obj.Topics {
+		w.WriteBytes(obj.Topics[_tmp2][:])
+	}
+	w.ListEnd(_tmp1)
+	w.WriteBytes(obj.Data)
+	w.WriteUint64(obj.BlockNumber)
+	w.WriteBytes(obj.TxHash[:])
+	w.WriteUint64(uint64(obj.TxIndex))
+	w.WriteBytes(obj.BlockHash[:])
+	w.WriteUint64(uint64(obj.Index))
+	w.WriteBool(obj.Removed)
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Log) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp0 Log
+	{
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		// Address:
+		var _tmp1 common.Address
+		if err := dec.ReadBytes(_tmp1[:]); err != nil {
+			return rlp.WrapDecodeError(err, ".Address")
+		}
+		_tmp0.Address = _tmp1
+		// Topics:
+		_tmp2 := []common.Hash{}
+		if _, err := dec.List(); err != nil {
+			return rlp.WrapDecodeError(err, ".Topics")
+		}
+		for dec.MoreDataInList() {
+			var _tmp3 common.Hash
+			if err := dec.ReadBytes(_tmp3[:]); err != nil {
+				return rlp.WrapDecodeError(err, ".Topics["+strconv.Itoa(len(_tmp2))+"]")
+			}
+			_tmp2 = append(_tmp2, _tmp3)
+		}
+		if err := dec.ListEnd(); err != nil {
+			return rlp.WrapDecodeError(err, ".Topics")
+		}
+		_tmp0.Topics = _tmp2
+		// Data:
+		_tmp4, err := dec.Bytes()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Data")
+		}
+		_tmp0.Data = _tmp4
+		// BlockNumber:
+		_tmp5, err := dec.Uint64()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".BlockNumber")
+		}
+		_tmp0.BlockNumber = _tmp5
+		// TxHash:
+		var _tmp6 common.Hash
+		if err := dec.ReadBytes(_tmp6[:]); err != nil {
+			return rlp.WrapDecodeError(err, ".TxHash")
+		}
+		_tmp0.TxHash = _tmp6
+		// TxIndex:
+		_tmp7, err := dec.Uint64()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".TxIndex")
+		}
+		_tmp0.TxIndex = uint(_tmp7)
+		// BlockHash:
+		var _tmp8 common.Hash
+		if err := dec.ReadBytes(_tmp8[:]); err != nil {
+			return rlp.WrapDecodeError(err, ".BlockHash")
+		}
+		_tmp0.BlockHash = _tmp8
+		// Index:
+		_tmp9, err := dec.Uint64()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Index")
+		}
+		_tmp0.Index = uint(_tmp9)
+		// Removed:
+		_tmp10, err := dec.Bool()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Removed")
+		}
+		_tmp0.Removed = _tmp10
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	*obj = _tmp0
+	return nil
+}
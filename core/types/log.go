@@ -2,6 +2,9 @@ package types
 
 import "awesomeProject/common"
 
+//go:generate go run ../../rlp/rlpgen -type Log -out gen_log_rlp.go
+
+// Log represents a contract log event.
 type Log struct {
 	// Consensus fields:
 	// address of the contract that generated the event
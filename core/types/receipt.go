@@ -37,6 +37,9 @@ type receiptRLP struct {
 	Logs              []*Log
 }
 
+// receiptRLPCodec is used by DeriveSha, which encodes all receipts of a block.
+var receiptRLPCodec = rlp.NewCodec[receiptRLP]()
+
 var (
 	receiptStatusFailedRLP     = []byte{}
 	receiptStatusSuccessfulRLP = []byte{0x01}
@@ -58,13 +61,13 @@ func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
 	data := &receiptRLP{r.statusEncoding(), r.CumulativeGasUsed, r.Bloom, r.Logs}
 	switch r.Type {
 	case LegacyTxType:
-		rlp.Encode(w, data)
+		receiptRLPCodec.Encode(w, data)
 	case AccessListTxType:
 		w.WriteByte(AccessListTxType)
-		rlp.Encode(w, data)
+		receiptRLPCodec.Encode(w, data)
 	case DynamicFeeTxType:
 		w.WriteByte(DynamicFeeTxType)
-		rlp.Encode(w, data)
+		receiptRLPCodec.Encode(w, data)
 	default:
 		// For unsupported types, write nothing. Since this is for
 		// DeriveSha, the error will be caught matching the derived hash
@@ -0,0 +1,148 @@
+package rlp
+
+import (
+	"awesomeProject/common"
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testBloom [256]byte
+
+type byteArrayStruct struct {
+	Addr  common.Address
+	Hash  common.Hash
+	Bloom testBloom
+	Other [5]byte
+}
+
+// byteArrayTestValues returns byte arrays of the fast-path sizes and of
+// another size, together with their expected encoding.
+func byteArrayTestValues() []struct {
+	val  interface{}
+	want []byte
+} {
+	var (
+		addr  common.Address
+		hash  common.Hash
+		bloom testBloom
+		other [5]byte
+	)
+	for i := range bloom {
+		bloom[i] = byte(i)
+	}
+	copy(addr[:], bloom[1:])
+	copy(hash[:], bloom[2:])
+	copy(other[:], bloom[3:])
+
+	header := func(size int) []byte {
+		if size < 56 {
+			return []byte{0x80 + byte(size)}
+		}
+		return []byte{0xB9, byte(size >> 8), byte(size)}
+	}
+	str := func(b []byte) []byte {
+		return append(header(len(b)), b...)
+	}
+	all := append(append(append(str(addr[:]), str(hash[:])...), str(bloom[:])...), str(other[:])...)
+	list := append(header(len(all)), all...)
+	list[0] = 0xF9 // list header, the content is longer than 256 bytes
+
+	return []struct {
+		val  interface{}
+		want []byte
+	}{
+		{addr, str(addr[:])},
+		{hash, str(hash[:])},
+		{bloom, str(bloom[:])},
+		{other, str(other[:])},
+		{byteArrayStruct{addr, hash, bloom, other}, list},
+	}
+}
+
+// TestByteArrayEncoding checks the encoding of addressable and non-addressable
+// byte arrays. Run it with -tags purego to check the other implementation of
+// byteArrayBytes.
+func TestByteArrayEncoding(t *testing.T) {
+	for _, test := range byteArrayTestValues() {
+		// Passing the value makes it non-addressable.
+		enc, err := EncodeToBytes(test.val)
+		if err != nil {
+			t.Fatalf("%T: %v", test.val, err)
+		}
+		if !bytes.Equal(enc, test.want) {
+			t.Errorf("%T: wrong encoding\ngot  %x\nwant %x", test.val, enc, test.want)
+		}
+
+		ptr := reflect.New(reflect.TypeOf(test.val))
+		ptr.Elem().Set(reflect.ValueOf(test.val))
+		enc, err = EncodeToBytes(ptr.Interface())
+		if err != nil {
+			t.Fatalf("*%T: %v", test.val, err)
+		}
+		if !bytes.Equal(enc, test.want) {
+			t.Errorf("*%T: wrong encoding\ngot  %x\nwant %x", test.val, enc, test.want)
+		}
+
+		dec := reflect.New(reflect.TypeOf(test.val))
+		if err := DecodeBytes(enc, dec.Interface()); err != nil {
+			t.Fatalf("%T: decode error: %v", test.val, err)
+		}
+		if !reflect.DeepEqual(dec.Elem().Interface(), test.val) {
+			t.Errorf("%T: decoded value differs", test.val)
+		}
+	}
+}
+
+func TestByteArrayEncodingAllocs(t *testing.T) {
+	for _, test := range byteArrayTestValues()[:3] {
+		val := reflect.ValueOf(test.val)
+		writer, err := cachedWriter(val.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(encBuffer)
+		buf.str = make([]byte, 0, 512)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf.reset()
+			writer(val, buf)
+		})
+		if allocs != 0 {
+			t.Errorf("%T: %v allocations, want 0", test.val, allocs)
+		}
+	}
+}
+
+func BenchmarkByteArrayEncoding(b *testing.B) {
+	for _, test := range byteArrayTestValues() {
+		val := reflect.ValueOf(test.val)
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		for _, v := range []struct {
+			name string
+			val  reflect.Value
+		}{
+			{"value", val},
+			{"addressable", ptr.Elem()},
+		} {
+			v := v
+			name := fmt.Sprintf("%s/%T/%s", byteArrayImpl, test.val, v.name)
+			b.Run(name, func(b *testing.B) {
+				writer, err := cachedWriter(v.val.Type())
+				if err != nil {
+					b.Fatal(err)
+				}
+				buf := new(encBuffer)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					buf.reset()
+					if err := writer(v.val, buf); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package rlp
+
+import (
+	"awesomeProject/rlp/internal/rlpstruct"
+	"reflect"
+	"sync"
+)
+
+// EncodeFunc writes the RLP encoding of val to w. It is used for types which
+// can't implement Encoder because they are defined in another package.
+type EncodeFunc func(w EncoderBuffer, val reflect.Value) error
+
+// DecodeFunc reads one value from s and stores it into val, which is
+// settable. It is the counterpart of EncodeFunc.
+type DecodeFunc func(s *Stream, val reflect.Value) error
+
+type codec struct {
+	enc EncodeFunc
+	dec DecodeFunc
+}
+
+// RegisterCodec sets the functions used to encode and decode values of type typ.
+// Registered codecs take precedence over all other encoding rules, including
+// the Encoder and Decoder interfaces. Either function may be nil, the regular
+// rules apply to that direction then.
+//
+// Values which refer to typ, e.g. pointers to typ or structs with a field of
+// type typ, use the codec as well. Registering a codec for a type that has a
+// codec already replaces it.
+//
+// RegisterCodec is meant to be called during program initialization. It is safe
+// for concurrent use, but encoding and decoding operations which are in progress
+// while the codec is registered may still use the previous rules.
+func RegisterCodec(typ reflect.Type, enc EncodeFunc, dec DecodeFunc) {
+	if typ == nil {
+		panic("rlp: RegisterCodec with nil type")
+	}
+	theTC.registerCodec(typ, &codec{enc, dec})
+}
+
+func (c *typeCache) registerCodec(typ reflect.Type, cd *codec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	codecs := make(map[reflect.Type]*codec, len(c.codecs)+1)
+	for k, v := range c.codecs {
+		codecs[k] = v
+	}
+	codecs[typ] = cd
+	c.codecs = codecs
+
+	// Cached type info may have been generated for types containing typ.
+	// Start over with an empty cache. Concurrent readers keep using the
+	// map they have loaded, which is never modified.
+	c.cur.Store(make(map[typekey]*typeinfo))
+	c.values.Store(new(sync.Map))
+}
+
+func (i *typeinfo) generateCodec(typ reflect.Type, tags rlpstruct.Tags, cd *codec) {
+	if cd.enc != nil {
+		i.writer = makeCodecWriter(cd.enc)
+	} else {
+		i.writer, i.writerErr = makeWriter(typ, tags)
+	}
+	if cd.dec != nil {
+		i.decoder = decoder(cd.dec)
+	} else {
+		i.decoder, i.decoderErr = makeDecoder(typ, tags)
+	}
+}
+
+func makeCodecWriter(fn EncodeFunc) writer {
+	return func(val reflect.Value, w *encBuffer) error {
+		return fn(EncoderBuffer{buf: w}, val)
+	}
+}
@@ -0,0 +1,89 @@
+package rlp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// cancelOnEncode cancels its context when it is encoded.
+type cancelOnEncode struct {
+	cancel context.CancelFunc
+}
+
+func (c cancelOnEncode) EncodeRLP(w io.Writer) error {
+	c.cancel()
+	_, err := w.Write([]byte{0x01})
+	return err
+}
+
+func TestEncodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var buf bytes.Buffer
+	if err := EncodeContext(ctx, &buf, []uint{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex("C3010203"); !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("wrong encoding %x, want %x", buf.Bytes(), want)
+	}
+
+	cancel()
+	buf.Reset()
+	if err := EncodeContext(ctx, &buf, uint(1)); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if buf.Len() > 0 {
+		t.Fatalf("output written after cancellation: %x", buf.Bytes())
+	}
+}
+
+func TestEncodeContextBetweenElements(t *testing.T) {
+	for _, opts := range []EncodeOptions{{}, {ParallelThreshold: 1, Workers: 2}} {
+		ctx, cancel := context.WithCancel(context.Background())
+		elems := []interface{}{uint(1), cancelOnEncode{cancel}, uint(2)}
+		var buf bytes.Buffer
+		w := NewEncoderBuffer(&buf)
+		w.buf.opts = opts
+		err := EncodeContext(ctx, w, elems)
+		if err != context.Canceled {
+			t.Errorf("opts %+v: got error %v, want %v", opts, err, context.Canceled)
+		}
+		// The context of the EncoderBuffer is restored.
+		if w.buf.ctx != nil {
+			t.Errorf("opts %+v: context left in buffer", opts)
+		}
+		cancel()
+	}
+}
+
+func TestStreamContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	input := unhex("C3010203")
+	s := NewStream(bytes.NewReader(input), 0)
+	s.SetContext(ctx)
+	if _, err := s.List(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Uint64(); err != nil || v != 1 {
+		t.Fatalf("got %d, %v; want 1", v, err)
+	}
+	cancel()
+	if _, err := s.Uint64(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	var v []uint
+	s.Reset(bytes.NewReader(input), 0)
+	s.SetContext(ctx)
+	if err := s.Decode(&v); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Decode: got error %v, want %v", err, context.Canceled)
+	}
+
+	// Reset clears the context.
+	s.Reset(bytes.NewReader(input), 0)
+	if err := s.Decode(&v); err != nil {
+		t.Fatalf("Decode after Reset: %v", err)
+	}
+}
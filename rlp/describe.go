@@ -0,0 +1,260 @@
+package rlp
+
+import (
+	"awesomeProject/rlp/internal/rlpstruct"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaKind says which encoding rule applies to a type.
+type SchemaKind int
+
+const (
+	SchemaRaw       SchemaKind = iota // RawValue, written as is
+	SchemaBigInt                      // big.Int and *big.Int
+	SchemaUint256                     // u256.Int and *u256.Int
+	SchemaPointer                     // pointer to another type
+	SchemaEncoder                     // type implementing Encoder
+	SchemaCodec                       // type with a codec registered by RegisterCodec
+	SchemaUint                        // unsigned integer
+	SchemaBool                        // bool
+	SchemaString                      // string
+	SchemaBytes                       // byte slice
+	SchemaByteArray                   // byte array
+	SchemaList                        // slice or array of other types
+	SchemaStruct                      // struct, encoded as a list of its fields
+	SchemaInterface                   // interface, encoded as its dynamic value
+	SchemaTyped                       // interface with implementations registered by RegisterType
+	SchemaMap                         // map with rlp:"map" tag, encoded as list of key/value pairs
+)
+
+var schemaKindNames = [...]string{
+	SchemaRaw:       "raw value",
+	SchemaBigInt:    "big integer",
+	SchemaUint256:   "uint256",
+	SchemaPointer:   "pointer",
+	SchemaEncoder:   "custom encoder",
+	SchemaCodec:     "registered codec",
+	SchemaUint:      "integer",
+	SchemaBool:      "bool",
+	SchemaString:    "string",
+	SchemaBytes:     "bytes",
+	SchemaByteArray: "byte array",
+	SchemaList:      "list",
+	SchemaStruct:    "struct",
+	SchemaInterface: "interface",
+	SchemaTyped:     "typed interface",
+	SchemaMap:       "map",
+}
+
+func (k SchemaKind) String() string {
+	if k >= 0 && int(k) < len(schemaKindNames) {
+		return schemaKindNames[k]
+	}
+	return fmt.Sprintf("SchemaKind(%d)", int(k))
+}
+
+// Schema describes how values of a type are encoded.
+type Schema struct {
+	Type reflect.Type
+	Kind SchemaKind
+
+	Len       int              // length of byte arrays
+	Nil       Kind             // encoding of nil pointers, String or List
+	Key       *Schema          // key of maps
+	Elem      *Schema          // element of lists and maps, target of pointers
+	Fields    []*SchemaField   // encoded fields of structs, in list order
+	Variants  []*SchemaVariant // registered implementations of typed interfaces
+	Recursive bool             // type refers to itself, Fields/Elem are not repeated
+}
+
+// SchemaField is an encoded struct field.
+type SchemaField struct {
+	Name     string // selector of the field, e.g. "Sig.R" for fields of inlined structs
+	Index    []int  // index sequence of the field in the Go struct, see reflect.Type.FieldByIndex
+	Position int    // position of the field in the encoded list
+	Optional bool
+	Tail     bool
+	Schema   *Schema
+}
+
+// SchemaVariant is an implementation of a typed interface.
+type SchemaVariant struct {
+	Legacy bool // encoded without type byte
+	ID     byte
+	Schema *Schema
+}
+
+// Describe returns the schema of typ, i.e. the encoding rules that Encode
+// applies to values of the type, after all struct tags are processed.
+func Describe(typ reflect.Type) (*Schema, error) {
+	if _, err := cachedWriter(typ); err != nil {
+		return nil, err
+	}
+	theTC.mu.Lock()
+	defer theTC.mu.Unlock()
+
+	d := describer{tc: theTC, active: make(map[reflect.Type]bool)}
+	return d.describe(typ, rlpstruct.Tags{}), nil
+}
+
+// describer walks types like makeWriter. It runs with the type cache lock
+// held, for access to the registered codecs and interfaces.
+type describer struct {
+	tc     *typeCache
+	active map[reflect.Type]bool // structs and interfaces being described
+}
+
+func (d *describer) describe(typ reflect.Type, tags rlpstruct.Tags) *Schema {
+	s := &Schema{Type: typ}
+	if cd := d.tc.codecs[typ]; cd != nil && cd.enc != nil {
+		s.Kind = SchemaCodec
+		return s
+	}
+	kind := typ.Kind()
+	switch {
+	case typ == rawValueType:
+		s.Kind = SchemaRaw
+	case typ.AssignableTo(reflect.PtrTo(bigInt)), typ.AssignableTo(bigInt):
+		s.Kind = SchemaBigInt
+	case typ == reflect.PtrTo(u256Int), typ == u256Int:
+		s.Kind = SchemaUint256
+	case kind == reflect.Ptr:
+		s.Kind = SchemaPointer
+		s.Nil = typeNilKind(typ.Elem(), tags)
+		s.Elem = d.describe(typ.Elem(), rlpstruct.Tags{})
+	case reflect.PtrTo(typ).Implements(encoderInterface):
+		s.Kind = SchemaEncoder
+	case isUint(kind):
+		s.Kind = SchemaUint
+	case kind == reflect.Bool:
+		s.Kind = SchemaBool
+	case kind == reflect.String:
+		s.Kind = SchemaString
+	case kind == reflect.Slice && isByte(typ.Elem()):
+		s.Kind = SchemaBytes
+	case kind == reflect.Array && isByte(typ.Elem()):
+		s.Kind = SchemaByteArray
+		s.Len = typ.Len()
+	case kind == reflect.Slice || kind == reflect.Array:
+		s.Kind = SchemaList
+		s.Elem = d.describe(typ.Elem(), rlpstruct.Tags{})
+	case kind == reflect.Struct:
+		s.Kind = SchemaStruct
+		d.describeStruct(s)
+	case kind == reflect.Interface:
+		s.Kind = SchemaInterface
+		d.describeInterface(s, tags)
+	case kind == reflect.Map && tags.Map:
+		s.Kind = SchemaMap
+		s.Key = d.describe(typ.Key(), rlpstruct.Tags{})
+		s.Elem = d.describe(typ.Elem(), rlpstruct.Tags{})
+	}
+	return s
+}
+
+func (d *describer) describeStruct(s *Schema) {
+	if d.active[s.Type] {
+		s.Recursive = true
+		return
+	}
+	d.active[s.Type] = true
+	defer delete(d.active, s.Type)
+
+	// Errors were reported by cachedWriter already.
+	fields, tags, _ := processStructFields(s.Type)
+	for i, f := range fields {
+		s.Fields = append(s.Fields, &SchemaField{
+			Name:     fieldName(s.Type, f.Path),
+			Index:    f.Path,
+			Position: i,
+			Optional: tags[i].Optional,
+			Tail:     tags[i].Tail,
+			Schema:   d.describe(s.Type.FieldByIndex(f.Path).Type, tags[i]),
+		})
+	}
+}
+
+func (d *describer) describeInterface(s *Schema, tags rlpstruct.Tags) {
+	ti, _ := d.tc.lookupTyped(s.Type, tags.Typed)
+	if ti == nil {
+		return
+	}
+	s.Kind = SchemaTyped
+	if d.active[s.Type] {
+		s.Recursive = true
+		return
+	}
+	d.active[s.Type] = true
+	defer delete(d.active, s.Type)
+
+	if ti.legacy != nil {
+		s.Variants = append(s.Variants, &SchemaVariant{Legacy: true, Schema: d.describe(ti.legacy, rlpstruct.Tags{})})
+	}
+	ids := make([]int, 0, len(ti.byID))
+	for id := range ti.byID {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		typ := ti.byID[byte(id)]
+		s.Variants = append(s.Variants, &SchemaVariant{ID: byte(id), Schema: d.describe(typ, rlpstruct.Tags{})})
+	}
+}
+
+// String returns the schema as an indented tree with one line per type.
+func (s *Schema) String() string {
+	var b strings.Builder
+	s.write(&b, "", "", "")
+	return b.String()
+}
+
+func (s *Schema) write(b *strings.Builder, indent, label, flags string) {
+	fmt.Fprintf(b, "%s%s%v: %s%s\n", indent, label, s.Type, s.describeKind(), flags)
+	indent += "  "
+	if s.Key != nil {
+		s.Key.write(b, indent, "key ", "")
+		s.Elem.write(b, indent, "value ", "")
+	} else if s.Elem != nil {
+		s.Elem.write(b, indent, "", "")
+	}
+	for _, f := range s.Fields {
+		label := fmt.Sprintf("[%d] %s ", f.Position, f.Name)
+		if f.Tail {
+			label = fmt.Sprintf("[%d...] %s ", f.Position, f.Name)
+		}
+		flags := ""
+		if f.Optional {
+			flags = ", optional"
+		}
+		if f.Tail {
+			flags += ", tail"
+		}
+		f.Schema.write(b, indent, label, flags)
+	}
+	for _, v := range s.Variants {
+		label := fmt.Sprintf("type %#02x ", v.ID)
+		if v.Legacy {
+			label = "legacy "
+		}
+		v.Schema.write(b, indent, label, "")
+	}
+}
+
+func (s *Schema) describeKind() string {
+	var desc string
+	switch s.Kind {
+	case SchemaPointer:
+		desc = "pointer, nil as " + strings.ToLower(s.Nil.String())
+	case SchemaByteArray:
+		desc = fmt.Sprintf("byte array of length %d", s.Len)
+	default:
+		desc = s.Kind.String()
+	}
+	if s.Recursive {
+		desc += " (recursive)"
+	}
+	return desc
+}
@@ -0,0 +1,74 @@
+package rlp
+
+import (
+	"reflect"
+	"testing"
+)
+
+type DescribeTestSig struct {
+	V, R, S uint64
+}
+
+type describeTestTx struct {
+	Nonce           uint64
+	DescribeTestSig `rlp:"inline"`
+	To              *[20]byte `rlp:"nil"`
+	Typed           typedTestIface
+	Next            *describeTestTx
+	Extra           []byte `rlp:"optional"`
+	Rest            []uint `rlp:"tail"`
+}
+
+func TestDescribe(t *testing.T) {
+	schema, err := Describe(reflect.TypeOf(describeTestTx{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `rlp.describeTestTx: struct
+  [0] Nonce uint64: integer
+  [1] DescribeTestSig.V uint64: integer
+  [2] DescribeTestSig.R uint64: integer
+  [3] DescribeTestSig.S uint64: integer
+  [4] To *[20]uint8: pointer, nil as string
+    [20]uint8: byte array of length 20
+  [5] Typed rlp.typedTestIface: typed interface
+    legacy rlp.typedTestLegacy: struct
+      [0] A uint: integer
+      [1] B uint: integer
+    type 0x01 rlp.typedTestA: struct
+      [0] X uint: integer
+      [1] Y []uint: list
+        uint: integer
+    type 0x02 *rlp.typedTestB: pointer, nil as list
+      rlp.typedTestB: struct
+        [0] S string: string
+    type 0x03 *rlp.typedTestCancel: pointer, nil as list
+      rlp.typedTestCancel: struct
+        [0] C rlp.cancelOnDecode: struct
+        [1] X uint: integer
+  [6] Next *rlp.describeTestTx: pointer, nil as list
+    rlp.describeTestTx: struct (recursive)
+  [7] Extra []uint8: bytes, optional
+  [8...] Rest []uint: list, tail
+    uint: integer
+`
+	if got := schema.String(); got != want {
+		t.Errorf("wrong schema\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if name := schema.Fields[2].Name; name != "DescribeTestSig.R" {
+		t.Errorf("wrong name %q for inlined field", name)
+	}
+	if index := schema.Fields[2].Index; !reflect.DeepEqual(index, []int{1, 1}) {
+		t.Errorf("wrong index %v for inlined field", index)
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	type invalid struct {
+		A uint `rlp:"tail"`
+		B uint
+	}
+	if _, err := Describe(reflect.TypeOf(invalid{})); err == nil {
+		t.Fatal("no error for invalid struct")
+	}
+}
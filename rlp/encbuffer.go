@@ -1,6 +1,8 @@
 package rlp
 
 import (
+	"awesomeProject/common/u256"
+	"context"
 	"io"
 	"math/big"
 	"reflect"
@@ -12,12 +14,22 @@ type encBuffer struct {
 	lheads  []listhead // all list headers
 	lhsize  int        // sum of sizes of all encoded list headers
 	sizebuf [9]byte    // auxiliary buffer for uint encoding
+	opts    EncodeOptions
+	ctx     context.Context // set by EncodeContext
 }
 
 func (buf *encBuffer) Write(b []byte) (int, error) {
 	buf.str = append(buf.str, b...)
 	return len(b), nil
 }
+
+// makeBytes creates the encoder output.
+func (b *encBuffer) makeBytes() []byte {
+	out := make([]byte, b.size())
+	b.copyTo(out)
+	return out
+}
+
 func (b *encBuffer) copyTo(dst []byte) {
 	strpos := 0
 	pos := 0
@@ -82,12 +94,21 @@ func (b *encBuffer) writeBytes(bet []byte) {
 	}
 }
 
+func (b *encBuffer) writeString(s string) {
+	if len(s) == 1 && s[0] <= 0x7f {
+		b.str = append(b.str, s[0])
+	} else {
+		b.encodeStringHeader(len(s))
+		b.str = append(b.str, s...)
+	}
+}
+
 func (b *encBuffer) encodeStringHeader(l int) {
 	if l < 56 {
 		b.str = append(b.str, 0x80+byte(l))
 	} else {
 		s := putint(b.sizebuf[1:], uint64(l))
-		b.sizebuf[0] = 0x80 + byte(s)
+		b.sizebuf[0] = 0xB7 + byte(s)
 		b.str = append(b.str, b.sizebuf[:s+1]...)
 	}
 }
@@ -104,7 +125,7 @@ func (b *encBuffer) endlist(offset int) {
 	lh := &b.lheads[offset]
 	//lh.size包含头的长度的总长度
 	lh.size = b.size() - lh.offset - lh.size
-	if lh.size <= 56 {
+	if lh.size < 56 {
 		b.lhsize++
 	} else {
 		b.lhsize += 1 + intsize(uint64(lh.size))
@@ -137,22 +158,64 @@ func (w *encBuffer) writeBigInt(i *big.Int) {
 	}
 }
 
+// writeUint256 writes z as an integer.
+func (w *encBuffer) writeUint256(z *u256.Int) {
+	bitlen := z.BitLen()
+	if bitlen <= 64 {
+		w.writeUint64(z.Uint64())
+		return
+	}
+	nBytes := byte((bitlen + 7) / 8)
+	b := z.Bytes32()
+	w.str = append(w.str, 0x80+nBytes)
+	w.str = append(w.str, b[32-nBytes:]...)
+}
+
 func (b *encBuffer) encode(val interface{}) error {
 	rval := reflect.ValueOf(val)
 	writer, err := cachedWriter(rval.Type())
 	if err != nil {
 		return err
 	}
-	return writer(rval, b)
+	if err := writer(rval, b); err != nil {
+		if encErr, ok := err.(*EncodeError); ok {
+			encErr.Type = rval.Type()
+		}
+		return err
+	}
+	return nil
 }
 
 func (buf *encBuffer) reset() {
 	buf.str = buf.str[:0]
 	buf.lhsize = 0
 	buf.lheads = buf.lheads[:0]
+	buf.opts = EncodeOptions{}
+	buf.ctx = nil
+}
+
+// checkContext returns the error of the context set by EncodeContext.
+func (buf *encBuffer) checkContext() error {
+	if buf.ctx == nil {
+		return nil
+	}
+	return buf.ctx.Err()
 }
 
-// 不知道干什么的
+// appendBuffer appends the content of src, which must not have open lists.
+func (buf *encBuffer) appendBuffer(src *encBuffer) {
+	for _, head := range src.lheads {
+		head.offset += len(buf.str)
+		buf.lheads = append(buf.lheads, head)
+	}
+	buf.str = append(buf.str, src.str...)
+	buf.lhsize += src.lhsize
+}
+
+// EncoderBuffer is a buffer for incremental encoding.
+//
+// The zero value is NOT ready for use. To get a usable buffer,
+// create it using NewEncoderBuffer or call Reset.
 type EncoderBuffer struct {
 	buf *encBuffer
 	dst io.Writer
@@ -160,10 +223,179 @@ type EncoderBuffer struct {
 	ownBuffer bool
 }
 
+// NewEncoderBuffer creates an encoder buffer.
+func NewEncoderBuffer(dst io.Writer) EncoderBuffer {
+	var w EncoderBuffer
+	w.Reset(dst)
+	return w
+}
+
+// Reset truncates the buffer and sets the output destination.
+func (w *EncoderBuffer) Reset(dst io.Writer) {
+	if w.buf != nil && !w.ownBuffer {
+		panic("can't Reset derived EncoderBuffer")
+	}
+
+	// If the destination writer has an *encBuffer, use it.
+	// Note that w.ownBuffer is left false here.
+	if dst != nil {
+		if outer := encBufferFromWriter(dst); outer != nil {
+			*w = EncoderBuffer{outer, nil, false}
+			return
+		}
+	}
+
+	// Get a fresh buffer.
+	if w.buf == nil {
+		w.buf = encBufferPool.Get().(*encBuffer)
+		w.ownBuffer = true
+	}
+	w.buf.reset()
+	w.dst = dst
+}
+
+// Flush writes encoded RLP data to the output writer. This can only be called once.
+// If you want to re-use the buffer after Flush, you must call Reset.
+func (w *EncoderBuffer) Flush() error {
+	var err error
+	if w.dst != nil {
+		err = w.buf.writeTo(w.dst)
+	}
+	// Release the internal buffer.
+	if w.ownBuffer {
+		encBufferPool.Put(w.buf)
+	}
+	*w = EncoderBuffer{}
+	return err
+}
+
+// ToBytes returns the encoded bytes.
+func (w *EncoderBuffer) ToBytes() []byte {
+	return w.buf.makeBytes()
+}
+
+// AppendToBytes appends the encoded bytes to dst.
+func (w *EncoderBuffer) AppendToBytes(dst []byte) []byte {
+	size := w.buf.size()
+	out := append(dst, make([]byte, size)...)
+	w.buf.copyTo(out[len(dst):])
+	return out
+}
+
+// Write appends b directly to the encoder output.
 func (w EncoderBuffer) Write(b []byte) (int, error) {
 	return w.buf.Write(b)
 }
 
+// List starts a list and returns its index. Call ListEnd with
+// the index after encoding the content of the list.
+func (w EncoderBuffer) List() int {
+	return w.buf.list()
+}
+
+// ListEnd finishes the given list.
+func (w EncoderBuffer) ListEnd(index int) {
+	w.buf.endlist(index)
+}
+
+// WriteUint64 encodes an unsigned integer.
+func (w EncoderBuffer) WriteUint64(i uint64) {
+	w.buf.writeUint64(i)
+}
+
+// WriteBigInt encodes a big.Int as an RLP string.
+// Note: Unlike with Encode, the sign of i is ignored.
+func (w EncoderBuffer) WriteBigInt(i *big.Int) {
+	w.buf.writeBigInt(i)
+}
+
+// WriteUint256 encodes z as an RLP string.
+func (w EncoderBuffer) WriteUint256(z *u256.Int) {
+	w.buf.writeUint256(z)
+}
+
+// WriteBytes encodes b as an RLP string.
+func (w EncoderBuffer) WriteBytes(b []byte) {
+	w.buf.writeBytes(b)
+}
+
+// WriteString encodes s as an RLP string.
+func (w EncoderBuffer) WriteString(s string) {
+	w.buf.writeString(s)
+}
+
+// WriteBool writes b as the integer 0 (false) or 1 (true).
+func (w EncoderBuffer) WriteBool(b bool) {
+	w.buf.writeBool(b)
+}
+
+// encReader is the io.Reader returned by EncodeToReader.
+// It releases its encbuf at EOF.
+type encReader struct {
+	buf    *encBuffer // the buffer we're reading from. this is nil when we're at EOF.
+	lhpos  int        // index of list header that we're reading
+	strpos int        // current position in string buffer
+	piece  []byte     // next piece to be read
+}
+
+func (r *encReader) Read(b []byte) (n int, err error) {
+	for {
+		if r.piece = r.next(); r.piece == nil {
+			// Put the encode buffer back into the pool at EOF when it
+			// is first encountered. Subsequent calls still return EOF
+			// as the error but the buffer is no longer valid.
+			if r.buf != nil {
+				encBufferPool.Put(r.buf)
+				r.buf = nil
+			}
+			return n, io.EOF
+		}
+		nn := copy(b[n:], r.piece)
+		n += nn
+		if nn < len(r.piece) {
+			// piece didn't fit, see you next time.
+			r.piece = r.piece[nn:]
+			return n, nil
+		}
+		r.piece = nil
+	}
+}
+
+// next returns the next piece of data to be read.
+// it returns nil at EOF.
+func (r *encReader) next() []byte {
+	switch {
+	case r.buf == nil:
+		return nil
+
+	case r.piece != nil:
+		// There is still data available for reading.
+		return r.piece
+
+	case r.lhpos < len(r.buf.lheads):
+		// We're before the last list header.
+		head := r.buf.lheads[r.lhpos]
+		sizebefore := head.offset - r.strpos
+		if sizebefore > 0 {
+			// String data before header.
+			p := r.buf.str[r.strpos:head.offset]
+			r.strpos += sizebefore
+			return p
+		}
+		r.lhpos++
+		return head.encode(r.buf.sizebuf[:])
+
+	case r.strpos < len(r.buf.str):
+		// String data at the end, after all list headers.
+		p := r.buf.str[r.strpos:]
+		r.strpos = len(r.buf.str)
+		return p
+
+	default:
+		return nil
+	}
+}
+
 func encBufferFromWriter(w io.Writer) *encBuffer {
 	switch w := w.(type) {
 	case EncoderBuffer:
@@ -0,0 +1,207 @@
+package rlp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncoderBufferWrite(t *testing.T) {
+	tests := []struct {
+		write func(w EncoderBuffer)
+		want  string
+	}{
+		{func(w EncoderBuffer) { w.WriteUint64(0) }, "80"},
+		{func(w EncoderBuffer) { w.WriteUint64(0x7F) }, "7F"},
+		{func(w EncoderBuffer) { w.WriteUint64(0x80) }, "8180"},
+		{func(w EncoderBuffer) { w.WriteUint64(0xFFFFFFFFFFFFFFFF) }, "88FFFFFFFFFFFFFFFF"},
+		{func(w EncoderBuffer) { w.WriteBool(true) }, "01"},
+		{func(w EncoderBuffer) { w.WriteBool(false) }, "80"},
+		{func(w EncoderBuffer) { w.WriteBigInt(big.NewInt(0)) }, "80"},
+		{func(w EncoderBuffer) { w.WriteBigInt(big.NewInt(0xFFFFFF)) }, "83FFFFFF"},
+		{func(w EncoderBuffer) { w.WriteBigInt(veryBigInt) }, "89FFFFFFFFFFFFFFFFFF"},
+		{func(w EncoderBuffer) { w.WriteBytes(nil) }, "80"},
+		{func(w EncoderBuffer) { w.WriteBytes([]byte{0x7F}) }, "7F"},
+		{func(w EncoderBuffer) { w.WriteBytes([]byte{0x80}) }, "8180"},
+		{func(w EncoderBuffer) { w.WriteString("dog") }, "83646F67"},
+		{func(w EncoderBuffer) { w.WriteString(strings.Repeat("a", 56)) }, "B838" + strings.Repeat("61", 56)},
+		{func(w EncoderBuffer) { w.Write([]byte{0xC1, 0x01}) }, "C101"},
+		{
+			func(w EncoderBuffer) {
+				l := w.List()
+				w.ListEnd(l)
+			},
+			"C0",
+		},
+		{
+			// [4, [5, 6], "dog"]
+			func(w EncoderBuffer) {
+				l1 := w.List()
+				w.WriteUint64(4)
+				l2 := w.List()
+				w.WriteUint64(5)
+				w.WriteUint64(6)
+				w.ListEnd(l2)
+				w.WriteString("dog")
+				w.ListEnd(l1)
+			},
+			"C804C2050683646F67",
+		},
+		{
+			// list with content larger than 55 bytes
+			func(w EncoderBuffer) {
+				l := w.List()
+				w.WriteString(strings.Repeat("a", 55))
+				w.ListEnd(l)
+			},
+			"F838B7" + strings.Repeat("61", 55),
+		},
+		{
+			// consecutive values without enclosing list
+			func(w EncoderBuffer) {
+				w.WriteUint64(1)
+				l := w.List()
+				w.ListEnd(l)
+				w.WriteUint64(2)
+			},
+			"01C002",
+		},
+	}
+
+	for i, test := range tests {
+		want := unhex(test.want)
+
+		// ToBytes and AppendToBytes.
+		w := NewEncoderBuffer(nil)
+		test.write(w)
+		if out := w.ToBytes(); !bytes.Equal(out, want) {
+			t.Errorf("test %d: ToBytes returned %X, want %X", i, out, want)
+		}
+		prefix := []byte{0xAA, 0xBB}
+		if out := w.AppendToBytes(prefix); !bytes.Equal(out, append(prefix, want...)) {
+			t.Errorf("test %d: AppendToBytes returned %X, want AABB%X", i, out, want)
+		}
+		w.Flush()
+
+		// Flush to writer.
+		var out bytes.Buffer
+		w = NewEncoderBuffer(&out)
+		test.write(w)
+		if err := w.Flush(); err != nil {
+			t.Errorf("test %d: Flush error: %v", i, err)
+		}
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Errorf("test %d: Flush wrote %X, want %X", i, out.Bytes(), want)
+		}
+	}
+}
+
+func TestEncoderBufferReset(t *testing.T) {
+	var out1, out2 bytes.Buffer
+	w := NewEncoderBuffer(&out1)
+	w.WriteString("dog")
+	// Reset discards the content written so far.
+	w.Reset(&out1)
+	w.WriteUint64(1)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out1.Bytes(), unhex("01")) {
+		t.Fatalf("first Flush wrote %X, want 01", out1.Bytes())
+	}
+
+	// The buffer can be used again after Flush.
+	w.Reset(&out2)
+	l := w.List()
+	w.WriteUint64(2)
+	w.ListEnd(l)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out2.Bytes(), unhex("C102")) {
+		t.Fatalf("second Flush wrote %X, want C102", out2.Bytes())
+	}
+	if out1.Len() != 1 {
+		t.Fatalf("second Flush modified first output: %X", out1.Bytes())
+	}
+}
+
+func TestEncoderBufferFlushError(t *testing.T) {
+	w := NewEncoderBuffer(&failWriter{n: 1})
+	l := w.List()
+	w.WriteString("dog")
+	w.ListEnd(l)
+	if err := w.Flush(); err != errWriteFailed {
+		t.Fatalf("Flush returned %v, want %v", err, errWriteFailed)
+	}
+}
+
+// encoderBufferStruct encodes itself through an EncoderBuffer created
+// on the writer given to EncodeRLP.
+type encoderBufferStruct struct {
+	A uint64
+	B []string
+}
+
+func (s *encoderBufferStruct) EncodeRLP(w io.Writer) error {
+	buf := NewEncoderBuffer(w)
+	l := buf.List()
+	buf.WriteUint64(s.A)
+	inner := buf.List()
+	for _, str := range s.B {
+		buf.WriteString(str)
+	}
+	buf.ListEnd(inner)
+	buf.ListEnd(l)
+	return buf.Flush()
+}
+
+func TestEncoderBufferInEncoder(t *testing.T) {
+	val := []*encoderBufferStruct{{A: 1, B: []string{"dog"}}, {A: 2}}
+	enc, err := EncodeToBytes(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex("CAC601C483646F67C202C0"); !bytes.Equal(enc, want) {
+		t.Fatalf("wrong encoding %X, want %X", enc, want)
+	}
+
+	// The derived buffer writes into the outer one and can't be reset.
+	outer := NewEncoderBuffer(nil)
+	defer outer.Flush()
+	derived := NewEncoderBuffer(outer)
+	derived.WriteUint64(3)
+	if out := outer.ToBytes(); !bytes.Equal(out, unhex("03")) {
+		t.Fatalf("outer buffer contains %X, want 03", out)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Reset of derived buffer did not panic")
+		}
+	}()
+	derived.Reset(nil)
+}
+
+func ExampleEncoderBuffer() {
+	var w bytes.Buffer
+
+	// Encode [4, [5, 6]] to w.
+	buf := NewEncoderBuffer(&w)
+	l1 := buf.List()
+	buf.WriteUint64(4)
+	l2 := buf.List()
+	buf.WriteUint64(5)
+	buf.WriteUint64(6)
+	buf.ListEnd(l2)
+	buf.ListEnd(l1)
+
+	if err := buf.Flush(); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%X\n", w.Bytes())
+	// Output:
+	// C404C20506
+}
@@ -1,7 +1,9 @@
 package rlp
 
 import (
+	"awesomeProject/common/u256"
 	"awesomeProject/rlp/internal/rlpstruct"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +11,16 @@ import (
 	"reflect"
 )
 
+var (
+	// Common encoded values.
+	// These are useful when implementing EncodeRLP.
+
+	// EmptyString is the encoding of an empty string.
+	EmptyString = []byte{0x80}
+	// EmptyList is the encoding of an empty list.
+	EmptyList = []byte{0xC0}
+)
+
 type listhead struct {
 	offset int // index of this header in string data
 	size   int // total size of encoded data (including list headers)
@@ -29,23 +41,153 @@ func Encode(w io.Writer, val interface{}) error {
 	buf := getEncBuffer()
 	defer encBufferPool.Put(buf)
 	if err := buf.encode(val); err != nil {
-		return nil
+		return err
+	}
+	return buf.writeTo(w)
+}
+
+// EncodeContext is like Encode, but stops when ctx is canceled. ctx.Err() is
+// returned when encoding stops. Nothing is written to w in that case, unless
+// w is an EncoderBuffer.
+//
+// The context is checked before encoding and between the elements of lists
+// encoded by reflection. Types with an EncodeRLP method, including encoders
+// generated by rlpgen, are encoded without interruption. To stop between
+// the elements of such a list, encode one element per call.
+func EncodeContext(ctx context.Context, w io.Writer, val interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if buf := encBufferFromWriter(w); buf != nil {
+		prev := buf.ctx
+		buf.ctx = ctx
+		defer func() { buf.ctx = prev }()
+		return contextError(ctx, buf.encode(val))
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.ctx = ctx
+	if err := buf.encode(val); err != nil {
+		return contextError(ctx, err)
 	}
 	return buf.writeTo(w)
 }
 
+// contextError returns the error of ctx instead of err if encoding failed
+// because ctx was canceled.
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// EncodeToBytes returns the RLP encoding of val.
+// Please see the documentation of Encode for the encoding rules.
+func EncodeToBytes(val interface{}) ([]byte, error) {
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+
+	if err := buf.encode(val); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
+// EncodeToReader returns a reader from which the RLP encoding of val
+// can be read. The returned size is the total size of the encoded
+// data.
+//
+// Please see the documentation of Encode for the encoding rules.
+func EncodeToReader(val interface{}) (size int, r io.Reader, err error) {
+	buf := getEncBuffer()
+	if err := buf.encode(val); err != nil {
+		encBufferPool.Put(buf)
+		return 0, nil, err
+	}
+	// Note: can't put the reader back into the pool here
+	// because it is held by encReader. The reader puts it
+	// back when it has been fully consumed.
+	return buf.size(), &encReader{buf: buf}, nil
+}
+
+// EncodedSize returns the length of the RLP encoding of val. The encoded
+// bytes are never assembled, the size is computed from the string data
+// and list headers held by the encoder buffer.
+func EncodedSize(val interface{}) (int, error) {
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+
+	if err := buf.encode(val); err != nil {
+		return 0, err
+	}
+	return buf.size(), nil
+}
+
+// headsize returns the size of a list or string header
+// for a value of the given size.
+func headsize(size uint64) int {
+	if size < 56 {
+		return 1
+	}
+	return 1 + intsize(size)
+}
+
 func puthead(dst []byte, smalltag, largetag byte, size uint64) int {
 	if size < 56 {
 		dst[0] = smalltag + byte(size)
 		return 1
 	}
-	sizesize := putint(dst, size)
-	dst = append(dst[1:], largetag+byte(sizesize))
+	sizesize := putint(dst[1:], size)
+	dst[0] = largetag + byte(sizesize)
 	return sizesize + 1
 }
 
 var ErrNegativeBigInt = errors.New("rlp: cannot encode negative big.Int")
 
+// EncodeError is returned when a value inside the encoded value can't be
+// encoded. It records where the value is located, use errors.Is and
+// errors.As to look at the underlying error.
+type EncodeError struct {
+	Type reflect.Type // type of the value given to the encoder
+	Path string       // location of the failed value, e.g. ".Txs[42].Value"
+	Err  error
+}
+
+func (err *EncodeError) Error() string {
+	var name string
+	if typ := err.Type; typ != nil {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if name = typ.Name(); name == "" {
+			name = typ.String()
+		}
+	}
+	return fmt.Sprintf("%s%s: %v", name, err.Path, err.Err)
+}
+
+func (err *EncodeError) Unwrap() error {
+	return err.Err
+}
+
+// WrapEncodeError prepends path to the location recorded in err, wrapping
+// err into an *EncodeError if it isn't one already. It is used by encoders
+// generated with rlpgen.
+func WrapEncodeError(err error, path string) error {
+	return addEncodeContext(err, path)
+}
+
+// addEncodeContext prepends ctx to the path of err. It is called on the way
+// out of nested writers, so the path is built from the innermost value.
+func addEncodeContext(err error, ctx string) error {
+	if encErr, ok := err.(*EncodeError); ok {
+		encErr.Path = ctx + encErr.Path
+		return encErr
+	}
+	return &EncodeError{Path: ctx, Err: err}
+}
+
 // 不知道干嘛的
 var encoderInterface = reflect.TypeOf(new(Encoder)).Elem()
 
@@ -59,6 +201,10 @@ func makeWriter(p reflect.Type, tags rlpstruct.Tags) (writer, error) {
 		return writeBigIntPtr, nil
 	case p.AssignableTo(bigInt): //为什么是assignableto大整数，不是等于大整数
 		return writeBigIntNoPtr, nil
+	case p == reflect.PtrTo(u256Int):
+		return writeU256IntPtr, nil
+	case p == u256Int:
+		return writeU256IntNoPtr, nil
 	case kind == reflect.Ptr:
 		return makePtrWriter(p, tags)
 	case reflect.PtrTo(p).Implements(encoderInterface):
@@ -78,7 +224,9 @@ func makeWriter(p reflect.Type, tags rlpstruct.Tags) (writer, error) {
 	case kind == reflect.Struct:
 		return makeStructWriter(p)
 	case kind == reflect.Interface:
-		return writeInterface, nil
+		return makeInterfaceWriter(p, tags)
+	case kind == reflect.Map && tags.Map:
+		return makeMapWriter(p)
 	default:
 		return nil, fmt.Errorf("rlp: type %v is not RLP-serializable", p)
 	}
@@ -95,7 +243,7 @@ func makeEncoderWriter(typ reflect.Type) writer {
 			// package json simply doesn't call MarshalJSON for this case, but encodes the
 			// value as if it didn't implement the interface. We don't want to handle it that
 			// way.
-			return fmt.Errorf("rlp: unadressable value of type %v, EncodeRLP is pointer method", val.Type())
+			return fmt.Errorf("rlp: unaddressable value of type %v, EncodeRLP is pointer method", val.Type())
 		}
 		return val.Addr().Interface().(Encoder).EncodeRLP(w)
 	}
@@ -124,6 +272,30 @@ func writeBigIntNoPtr(val reflect.Value, w *encBuffer) error {
 	return nil
 }
 
+func writeU256IntPtr(val reflect.Value, w *encBuffer) error {
+	ptr := val.Interface().(*u256.Int)
+	if ptr == nil {
+		w.str = append(w.str, 0x80)
+		return nil
+	}
+	w.writeUint256(ptr)
+	return nil
+}
+
+func writeU256IntNoPtr(val reflect.Value, w *encBuffer) error {
+	if val.CanAddr() {
+		return writeU256IntPtr(val.Addr(), w)
+	}
+	// Copy the words instead of going through Interface, which would
+	// allocate.
+	var z u256.Int
+	for i := range z {
+		z[i] = val.Index(i).Uint()
+	}
+	w.writeUint256(&z)
+	return nil
+}
+
 func makeStructWriter(p reflect.Type) (writer, error) {
 	flieds, err := structFlieds(p)
 	if err != nil {
@@ -145,8 +317,8 @@ func makeStructWriter(p reflect.Type) (writer, error) {
 		writer = func(value reflect.Value, buffer *encBuffer) error {
 			offset := buffer.list()
 			for _, flied := range flieds {
-				if err := flied.info.writer(value.Field(flied.index), buffer); err != nil {
-					return err
+				if err := flied.info.writer(value.FieldByIndex(flied.index), buffer); err != nil {
+					return addEncodeContext(err, "."+flied.name)
 				}
 			}
 			buffer.endlist(offset)
@@ -158,17 +330,17 @@ func makeStructWriter(p reflect.Type) (writer, error) {
 			lastField := len(flieds) - 1
 			for ; lastField >= firstOptionalField; lastField-- {
 				//已经确定是optional的了，为什么还要判断是不是零值,好像意思是只有零值才能不编码
-				if !value.Field(flieds[lastField].index).IsZero() {
+				if !value.FieldByIndex(flieds[lastField].index).IsZero() {
 					break
 				}
 			}
+			offset := buffer.list()
 			for i := 0; i <= lastField; i++ {
-				offset := buffer.list()
-				if err := flieds[i].info.writer(value.Field(flieds[i].index), buffer); err != nil {
-					return err
+				if err := flieds[i].info.writer(value.FieldByIndex(flieds[i].index), buffer); err != nil {
+					return addEncodeContext(err, "."+flieds[i].name)
 				}
-				buffer.endlist(offset)
 			}
+			buffer.endlist(offset)
 			return nil
 		}
 	}
@@ -176,7 +348,7 @@ func makeStructWriter(p reflect.Type) (writer, error) {
 }
 
 func makeSliceWriter(p reflect.Type, tags rlpstruct.Tags) (writer, error) {
-	etpyeinfo := theTC.infoWhileGenerating(p.Elem(), tags)
+	etpyeinfo := theTC.infoWhileGenerating(p.Elem(), rlpstruct.Tags{})
 	if etpyeinfo.writerErr != nil {
 		return nil, etpyeinfo.writerErr
 	}
@@ -184,10 +356,16 @@ func makeSliceWriter(p reflect.Type, tags rlpstruct.Tags) (writer, error) {
 	//没理解tail的作用
 	if tags.Tail {
 		wfn = func(value reflect.Value, buffer *encBuffer) error {
+			if workers := buffer.opts.workers(value.Len()); workers > 0 {
+				return writeElemsParallel(value, buffer, etpyeinfo.writer, workers)
+			}
 			for i := 0; i < value.Len(); i++ {
-				if err := etpyeinfo.writer(value.Index(i), buffer); err != nil {
+				if err := buffer.checkContext(); err != nil {
 					return err
 				}
+				if err := etpyeinfo.writer(value.Index(i), buffer); err != nil {
+					return addEncodeContext(err, fmt.Sprint("[", i, "]"))
+				}
 			}
 			return nil
 		}
@@ -199,10 +377,20 @@ func makeSliceWriter(p reflect.Type, tags rlpstruct.Tags) (writer, error) {
 				return nil
 			}
 			listOffset := buffer.list()
+			if workers := buffer.opts.workers(vlen); workers > 0 {
+				if err := writeElemsParallel(value, buffer, etpyeinfo.writer, workers); err != nil {
+					return err
+				}
+				buffer.endlist(listOffset)
+				return nil
+			}
 			for i := 0; i < vlen; i++ {
-				if err := etpyeinfo.writer(value.Index(i), buffer); err != nil {
+				if err := buffer.checkContext(); err != nil {
 					return err
 				}
+				if err := etpyeinfo.writer(value.Index(i), buffer); err != nil {
+					return addEncodeContext(err, fmt.Sprint("[", i, "]"))
+				}
 			}
 			buffer.endlist(listOffset)
 			return nil
@@ -227,14 +415,7 @@ func writeBool(value reflect.Value, buffer *encBuffer) error {
 }
 
 func writeString(value reflect.Value, buffer *encBuffer) error {
-	str := value.String()
-	l := len(str)
-	if l == 1 && str[0] <= 0x7f {
-		buffer.str = append(buffer.str, str[0])
-	} else {
-		buffer.encodeStringHeader(l)
-		buffer.str = append(buffer.str, str...)
-	}
+	buffer.writeString(value.String())
 	return nil
 }
 
@@ -256,6 +437,17 @@ func writeInterface(value reflect.Value, buffer *encBuffer) error {
 	return writer(eval, buffer)
 }
 
+func makeInterfaceWriter(typ reflect.Type, ts rlpstruct.Tags) (writer, error) {
+	ti, err := theTC.lookupTyped(typ, ts.Typed)
+	if err != nil {
+		return nil, err
+	}
+	if ti != nil {
+		return ti.write, nil
+	}
+	return writeInterface, nil
+}
+
 func makePtrWriter(typ reflect.Type, ts rlpstruct.Tags) (writer, error) {
 	nilEncoding := byte(0xC0)
 	if typeNilKind(typ.Elem(), ts) == String {
@@ -282,12 +474,26 @@ func makeByteArrayWriter(p reflect.Type) writer {
 	case 1:
 		return writeLengthOneByteArray
 	default:
+		// Arrays which can be converted to one of the fixed-size array
+		// types don't need to be copied to the heap when they are not
+		// addressable.
+		var fixed reflect.Type
+		for _, t := range fixedByteArrayTypes {
+			if p.ConvertibleTo(t) {
+				fixed = t
+				break
+			}
+		}
 		return func(value reflect.Value, buffer *encBuffer) error {
 			//什么情况下数组是可寻址的
 			//var b = [3]int{1, 2, 3}
 			//fmt.Print(reflect.ValueOf(&b).Elem().Kind(), reflect.ValueOf(&b).Elem())
 			//a(reflect.ValueOf(&b).Elem())
 			if !value.CanAddr() {
+				if fixed != nil {
+					writeFixedByteArray(value.Convert(fixed), buffer)
+					return nil
+				}
 				copy := reflect.New(value.Type()).Elem()
 				copy.Set(value)
 				value = copy
@@ -301,6 +507,30 @@ func makeByteArrayWriter(p reflect.Type) writer {
 	}
 }
 
+// fixedByteArrayTypes are the array types of common.Address, common.Hash
+// and types.Bloom.
+var fixedByteArrayTypes = []reflect.Type{
+	reflect.TypeOf([20]byte{}),
+	reflect.TypeOf([32]byte{}),
+	reflect.TypeOf([256]byte{}),
+}
+
+// writeFixedByteArray writes a value of one of fixedByteArrayTypes. It must
+// only be used for non-addressable values: reflect copies an array to the heap
+// in Convert and Interface only if it is addressable, otherwise the interface
+// refers to the memory of value. The type switch then copies the array into a
+// local variable, which does not escape.
+func writeFixedByteArray(value reflect.Value, buffer *encBuffer) {
+	switch v := value.Interface().(type) {
+	case [20]byte:
+		buffer.writeBytes(v[:])
+	case [32]byte:
+		buffer.writeBytes(v[:])
+	case [256]byte:
+		buffer.writeBytes(v[:])
+	}
+}
+
 func writeLengthOneByteArray(value reflect.Value, buffer *encBuffer) error {
 	b := byte(value.Index(0).Uint())
 	if b <= 0x7f {
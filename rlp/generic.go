@@ -0,0 +1,136 @@
+package rlp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Codec encodes and decodes values of type T. The encoding rules for T are
+// looked up once when the Codec is created, which saves the type cache lookup
+// that Encode and Decode perform on every call. Values are encoded through a
+// *T, so they are neither converted to interface{} nor copied.
+//
+// Codecs created before a call to RegisterCodec or RegisterType keep using
+// the rules that were in effect when they were created.
+type Codec[T any] struct {
+	typ        reflect.Type
+	writer     writer
+	writerErr  error
+	decoder    decoder
+	decoderErr error
+}
+
+// NewCodec creates a Codec for T. If T can't be encoded or decoded, the
+// error is returned by the respective methods of the Codec.
+func NewCodec[T any]() *Codec[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	info := theTC.info(typ)
+	return &Codec[T]{
+		typ:        typ,
+		writer:     info.writer,
+		writerErr:  info.writerErr,
+		decoder:    info.decoder,
+		decoderErr: info.decoderErr,
+	}
+}
+
+// cachedCodec returns the Codec of EncodeValue and DecodeValue for T.
+func cachedCodec[T any]() *Codec[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	values := theTC.values.Load().(*sync.Map)
+	if c, ok := values.Load(typ); ok {
+		return c.(*Codec[T])
+	}
+	c := NewCodec[T]()
+	values.Store(typ, c)
+	return c
+}
+
+// Encode writes the RLP encoding of *v to w. v must not be nil.
+func (c *Codec[T]) Encode(w io.Writer, v *T) error {
+	if buf := encBufferFromWriter(w); buf != nil {
+		return c.write(buf, v)
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err := c.write(buf, v); err != nil {
+		return err
+	}
+	return buf.writeTo(w)
+}
+
+// EncodeToBytes returns the RLP encoding of *v. v must not be nil.
+func (c *Codec[T]) EncodeToBytes(v *T) ([]byte, error) {
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err := c.write(buf, v); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
+func (c *Codec[T]) write(buf *encBuffer, v *T) error {
+	if c.writerErr != nil {
+		return c.writerErr
+	}
+	if err := c.writer(reflect.ValueOf(v).Elem(), buf); err != nil {
+		if encErr, ok := err.(*EncodeError); ok {
+			encErr.Type = c.typ
+		}
+		return err
+	}
+	return nil
+}
+
+// Decode reads the next value from s.
+func (c *Codec[T]) Decode(s *Stream) (T, error) {
+	var v T
+	if c.decoderErr != nil {
+		return v, c.decoderErr
+	}
+	if err := c.decoder(s, reflect.ValueOf(&v).Elem()); err != nil {
+		if decErr, ok := err.(*decodeError); ok && len(decErr.ctx) > 0 {
+			// Add decode target type to error so the path has a root.
+			decErr.ctx = append(decErr.ctx, c.typ.String())
+		}
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// DecodeBytes decodes b, which must contain exactly one value.
+func (c *Codec[T]) DecodeBytes(b []byte) (T, error) {
+	r := bytes.NewReader(b)
+
+	stream := streamPool.Get().(*Stream)
+	defer streamPool.Put(stream)
+
+	stream.Reset(r, uint64(len(b)))
+	v, err := c.Decode(stream)
+	if err != nil {
+		return v, err
+	}
+	if r.Len() > 0 {
+		var zero T
+		return zero, ErrMoreThanOneValue
+	}
+	return v, nil
+}
+
+// EncodeValue writes the RLP encoding of v to w. Unlike Encode, it uses the
+// encoding rules of the static type T. This matters when T is an interface
+// type with implementations registered by RegisterType.
+//
+// Unless T is a pointer type, v is copied to the heap. Use a Codec to encode
+// large values without copying them.
+func EncodeValue[T any](w io.Writer, v T) error {
+	return cachedCodec[T]().Encode(w, &v)
+}
+
+// DecodeValue decodes b, which must contain exactly one value, as a T.
+func DecodeValue[T any](b []byte) (T, error) {
+	return cachedCodec[T]().DecodeBytes(b)
+}
@@ -0,0 +1,149 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type genericTestStruct struct {
+	A uint64
+	B [32]byte
+	C []string
+	D *genericTestStruct `rlp:"nil"`
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	val := genericTestStruct{A: 1, B: [32]byte{2}, C: []string{"a", "b"}, D: &genericTestStruct{A: 3, C: []string{}}}
+	want, err := EncodeToBytes(&val)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCodec[genericTestStruct]()
+	enc, err := c.EncodeToBytes(&val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, want) {
+		t.Fatalf("EncodeToBytes: wrong encoding\ngot  %x\nwant %x", enc, want)
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, &val); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("Encode: wrong encoding\ngot  %x\nwant %x", buf.Bytes(), want)
+	}
+	dec, err := c.DecodeBytes(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, val) {
+		t.Fatalf("DecodeBytes: got %+v, want %+v", dec, val)
+	}
+
+	// Pointer types.
+	pc := NewCodec[*genericTestStruct]()
+	ptr := &val
+	if enc, err = pc.EncodeToBytes(&ptr); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, want) {
+		t.Fatalf("pointer codec: wrong encoding\ngot  %x\nwant %x", enc, want)
+	}
+	s := NewStream(bytes.NewReader(enc), 0)
+	decPtr, err := pc.Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decPtr, &val) {
+		t.Fatalf("pointer codec: got %+v, want %+v", decPtr, &val)
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	c := NewCodec[genericTestStruct]()
+	if _, err := c.DecodeBytes(unhex("C40180C0C0")); err == nil {
+		t.Fatal("no error for short byte array")
+	} else if want := "rlp: input string too short for [32]uint8, decoding into rlp.genericTestStruct.B"; err.Error() != want {
+		t.Errorf("wrong error\ngot:  %v\nwant: %v", err, want)
+	}
+	enc, _ := c.EncodeToBytes(&genericTestStruct{})
+	if _, err := c.DecodeBytes(append(enc, 0x01)); err != ErrMoreThanOneValue {
+		t.Errorf("trailing data: got error %v, want %v", err, ErrMoreThanOneValue)
+	}
+
+	type unsupported struct{ F func() }
+	uc := NewCodec[unsupported]()
+	if _, err := uc.EncodeToBytes(&unsupported{}); err == nil {
+		t.Error("no encode error for unsupported type")
+	}
+	if _, err := uc.DecodeBytes(unhex("C0")); err == nil {
+		t.Error("no decode error for unsupported type")
+	}
+}
+
+func TestEncodeValueInterface(t *testing.T) {
+	// EncodeValue uses the registered implementations of the static type,
+	// Encode only sees the dynamic type.
+	var v typedTestIface = typedTestA{X: 5, Y: []uint{1, 2}}
+	var buf bytes.Buffer
+	if err := EncodeValue(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex("8601C405C20102"); !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("wrong encoding %x, want %x", buf.Bytes(), want)
+	}
+	dec, err := DecodeValue[typedTestIface](buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, v) {
+		t.Fatalf("decoded %+v, want %+v", dec, v)
+	}
+}
+
+func TestCachedCodec(t *testing.T) {
+	type genericTestCached struct{ A uint }
+	if cachedCodec[genericTestCached]() != cachedCodec[genericTestCached]() {
+		t.Fatal("codec not reused")
+	}
+	var buf bytes.Buffer
+	EncodeValue(&buf, genericTestCached{1})
+	if want := unhex("C101"); !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("wrong encoding %x, want %x", buf.Bytes(), want)
+	}
+
+	// Registering a codec for the type drops the cached one.
+	errCodec := errors.New("codec called")
+	RegisterCodec(reflect.TypeOf(genericTestCached{}), func(w EncoderBuffer, val reflect.Value) error {
+		return errCodec
+	}, nil)
+	if err := EncodeValue(new(bytes.Buffer), genericTestCached{1}); !errors.Is(err, errCodec) {
+		t.Fatalf("got error %v, want %v", err, errCodec)
+	}
+}
+
+func TestCodecEncodeAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts differ with the race detector")
+	}
+	if byteArrayImpl == "purego" {
+		t.Skip("byteArrayBytes allocates without package unsafe")
+	}
+	c := NewCodec[genericTestStruct]()
+	val := genericTestStruct{A: 1, B: [32]byte{2}, C: []string{"a", "b"}}
+	buf := new(encBuffer)
+	buf.str = make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.reset()
+		if err := c.write(buf, &val); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations, want 0", allocs)
+	}
+}
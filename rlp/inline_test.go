@@ -0,0 +1,154 @@
+package rlp
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type InlineTestSig struct {
+	V, R, S *big.Int
+}
+
+// The types below have the same encoding.
+type (
+	inlineTestTx struct {
+		Nonce         uint64
+		Data          []byte
+		InlineTestSig `rlp:"inline"`
+	}
+	inlineTestTxPos struct {
+		InlineTestSig `rlp:"inline,pos=2"`
+		Nonce         uint64 `rlp:"pos=0"`
+		Data          []byte `rlp:"pos=1"`
+	}
+	inlineTestTxPlain struct {
+		Nonce   uint64
+		Data    []byte
+		V, R, S *big.Int
+	}
+)
+
+func TestInlineLayout(t *testing.T) {
+	sig := InlineTestSig{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	want := unhex("C50701010203")
+	for _, val := range []interface{}{
+		&inlineTestTx{Nonce: 7, Data: []byte{1}, InlineTestSig: sig},
+		&inlineTestTxPos{Nonce: 7, Data: []byte{1}, InlineTestSig: sig},
+		&inlineTestTxPlain{Nonce: 7, Data: []byte{1}, V: sig.V, R: sig.R, S: sig.S},
+	} {
+		enc, err := EncodeToBytes(val)
+		if err != nil {
+			t.Fatalf("%T: %v", val, err)
+		}
+		if !bytes.Equal(enc, want) {
+			t.Errorf("%T: wrong encoding %x, want %x", val, enc, want)
+		}
+		dec := reflect.New(reflect.TypeOf(val).Elem())
+		if err := DecodeBytes(enc, dec.Interface()); err != nil {
+			t.Fatalf("%T: decode error: %v", val, err)
+		}
+		if !reflect.DeepEqual(dec.Interface(), val) {
+			t.Errorf("%T: decoded %+v, want %+v", val, dec.Interface(), val)
+		}
+	}
+}
+
+func TestPositionOptional(t *testing.T) {
+	type opt struct {
+		A uint `rlp:"pos=0"`
+		B uint `rlp:"pos=2,optional"`
+		C uint `rlp:"pos=1"`
+	}
+	tests := []struct {
+		val  opt
+		want string
+	}{
+		{opt{A: 1, C: 3}, "C20103"},
+		{opt{A: 1, B: 2, C: 3}, "C3010302"},
+	}
+	for _, test := range tests {
+		enc, err := EncodeToBytes(&test.val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := unhex(test.want); !bytes.Equal(enc, want) {
+			t.Errorf("%+v: wrong encoding %x, want %x", test.val, enc, want)
+		}
+	}
+}
+
+type InlineTestPosX struct {
+	X uint `rlp:"pos=x"`
+}
+
+type InlineTestTail struct {
+	Rest []uint `rlp:"tail"`
+}
+
+func TestInlineTagErrors(t *testing.T) {
+	type dup struct {
+		InlineTestSig `rlp:"inline"`
+		R             uint
+	}
+	type gap struct {
+		A uint `rlp:"pos=0"`
+		B uint `rlp:"pos=2"`
+	}
+	type same struct {
+		A uint `rlp:"pos=0"`
+		B uint `rlp:"pos=0"`
+	}
+	type missing struct {
+		A uint `rlp:"pos=1"`
+		B uint
+	}
+	type notEmbedded struct {
+		S InlineTestSig `rlp:"inline"`
+	}
+	type invalidPos struct {
+		InlineTestPosX `rlp:"inline"`
+	}
+	type tailInline struct {
+		InlineTestTail `rlp:"inline"`
+		A              uint
+	}
+	tests := []struct {
+		val interface{}
+		err string
+	}{
+		{&dup{}, `rlp: invalid struct tag "" for rlp.dup.R (duplicate field name)`},
+		{&gap{}, `rlp: invalid struct tag "pos=2" for rlp.gap.B (no field at position 1)`},
+		{&same{}, `rlp: invalid struct tag "pos=0" for rlp.same.B (position already used by field "A")`},
+		{&missing{}, `rlp: invalid struct tag "" for rlp.missing.B (missing position, field "A" has one)`},
+		{&notEmbedded{}, `rlp: invalid struct tag "inline" for rlp.notEmbedded.S (field is not an embedded struct)`},
+		{&invalidPos{}, `rlp: invalid struct tag "pos=x" for rlp.invalidPos.InlineTestPosX.X (invalid position)`},
+		{&tailInline{}, `rlp: invalid struct tag "tail" for rlp.tailInline.Rest (must be on last field)`},
+	}
+	for _, test := range tests {
+		_, err := EncodeToBytes(test.val)
+		if err == nil {
+			t.Errorf("%T: expected encode error", test.val)
+		} else if err.Error() != test.err {
+			t.Errorf("%T: wrong encode error\ngot:  %v\nwant: %v", test.val, err, test.err)
+		}
+		err = DecodeBytes(unhex("C0"), test.val)
+		if err == nil {
+			t.Errorf("%T: expected decode error", test.val)
+		} else if err.Error() != test.err {
+			t.Errorf("%T: wrong decode error\ngot:  %v\nwant: %v", test.val, err, test.err)
+		}
+	}
+}
+
+func TestInlineErrorPaths(t *testing.T) {
+	_, err := EncodeToBytes(&inlineTestTx{InlineTestSig: InlineTestSig{V: big.NewInt(-1)}})
+	if want := "inlineTestTx.InlineTestSig.V: rlp: cannot encode negative big.Int"; err == nil || err.Error() != want {
+		t.Errorf("wrong encode error\ngot:  %v\nwant: %s", err, want)
+	}
+	err = DecodeBytes(unhex("C50701C00203"), new(inlineTestTx))
+	if want := "rlp: expected input string or byte for *big.Int, decoding into rlp.inlineTestTx.InlineTestSig.V"; err == nil || err.Error() != want {
+		t.Errorf("wrong decode error\ngot:  %v\nwant: %s", err, want)
+	}
+}
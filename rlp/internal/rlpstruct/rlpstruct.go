@@ -3,6 +3,8 @@ package rlpstruct
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -12,8 +14,18 @@ type Field struct {
 	Name     string
 	Index    int
 	Exported bool
+	Embedded bool
 	Type     Type
 	Tag      string
+
+	// Fields holds the fields of embedded struct types, they are needed
+	// for the rlp:"inline" tag.
+	Fields []Field
+
+	// Path is the index sequence of the field in the outermost struct.
+	// It is set by ParseFields and is longer than one element for the
+	// fields of inlined structs.
+	Path []int
 }
 
 type TagError struct {
@@ -71,14 +83,75 @@ type Tags struct {
 
 	// rlp:"-" ignores fields.
 	Ignored bool
+
+	// rlp:"typed=NAME" decodes an interface field using the implementations
+	// registered for the interface type NAME.
+	Typed string
+
+	// rlp:"map" encodes a map field as a list of key/value pairs, sorted by
+	// the encoding of the key.
+	Map bool
+
+	// rlp:"inline" encodes the fields of an embedded struct as if they were
+	// fields of the outer struct.
+	Inline bool
+
+	// rlp:"pos=N" sets the position of the field in the list. For inlined
+	// structs, it is the position of the first field. If any field of a
+	// struct has a position, all fields must have one.
+	Position    int
+	HasPosition bool
 }
 
 // 不懂为什么要去除
 func ProcessFields(allStructFields []Field) ([]Field, []Tags, error) {
+	fields, tags, err := ParseFields(allStructFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckOptionalOrder(fields, tags); err != nil {
+		return nil, nil, err
+	}
+	return fields, tags, nil
+}
+
+// ParseFields is like ProcessFields, but doesn't check that the fields
+// following an optional field are optional as well.
+//
+// The fields are returned in list order. Inlined structs are replaced by
+// their fields.
+func ParseFields(allStructFields []Field) ([]Field, []Tags, error) {
+	fields, tags, err := parseStruct(allStructFields, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]bool, len(fields))
+	for i, f := range fields {
+		if names[f.Name] {
+			return nil, nil, TagError{Field: f.Name, Err: "duplicate field name"}
+		}
+		names[f.Name] = true
+		if tags[i].Tail && i != len(fields)-1 {
+			return nil, nil, TagError{Field: f.Name, Tag: "tail", Err: "must be on last field"}
+		}
+	}
+	return fields, tags, nil
+}
+
+// fieldGroup is a field, or the fields of an inlined struct.
+type fieldGroup struct {
+	name   string
+	fields []Field
+	tags   []Tags
+	ts     Tags
+}
+
+// parseStruct parses the fields of one struct. outer is the path of the
+// struct in the outermost struct.
+func parseStruct(allStructFields []Field, outer []int) ([]Field, []Tags, error) {
 	lastPublic := lastPublicField(allStructFields)
 
-	var fields []Field
-	var tags []Tags
+	var groups []fieldGroup
 	for _, field := range allStructFields { //为什么只要未命名数据类型
 		if !field.Exported {
 			continue
@@ -90,9 +163,77 @@ func ProcessFields(allStructFields []Field) ([]Field, []Tags, error) {
 		if ts.Ignored {
 			continue
 		}
-		fields = append(fields, field)
-		tags = append(tags, ts)
+		path := append(outer[:len(outer):len(outer)], field.Index)
+		g := fieldGroup{name: field.Name, ts: ts}
+		if ts.Inline {
+			g.fields, g.tags, err = parseStruct(field.Fields, path)
+			if tagErr, ok := err.(TagError); ok {
+				tagErr.Field = field.Name + "." + tagErr.Field
+				return nil, nil, tagErr
+			} else if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			field.Path = path
+			g.fields, g.tags = []Field{field}, []Tags{ts}
+		}
+		groups = append(groups, g)
+	}
+	if err := sortPositions(groups); err != nil {
+		return nil, nil, err
 	}
+
+	var fields []Field
+	var tags []Tags
+	for _, g := range groups {
+		fields = append(fields, g.fields...)
+		tags = append(tags, g.tags...)
+	}
+	return fields, tags, nil
+}
+
+// sortPositions orders the groups of a struct by their rlp:"pos=N" tags,
+// checking that every position is used exactly once.
+func sortPositions(groups []fieldGroup) error {
+	first := -1
+	for i, g := range groups {
+		if g.ts.HasPosition {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	for _, g := range groups {
+		if !g.ts.HasPosition {
+			msg := fmt.Sprintf("missing position, field %q has one", groups[first].name)
+			return TagError{Field: g.name, Err: msg}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].ts.Position < groups[j].ts.Position
+	})
+	next := 0
+	for i, g := range groups {
+		tag := "pos=" + strconv.Itoa(g.ts.Position)
+		switch {
+		case g.ts.Position < next:
+			msg := fmt.Sprintf("position already used by field %q", groups[i-1].name)
+			return TagError{Field: g.name, Tag: tag, Err: msg}
+		case g.ts.Position > next:
+			msg := fmt.Sprintf("no field at position %d", next)
+			return TagError{Field: g.name, Tag: tag, Err: msg}
+		}
+		next += len(g.fields)
+	}
+	return nil
+}
+
+// CheckOptionalOrder checks that no required field follows an optional field.
+// The arguments are the results of ParseFields.
+func CheckOptionalOrder(fields []Field, tags []Tags) error {
 	//为什么optional的field后面必须都是optional
 	var anyOptional bool
 	var firstOptionalName string
@@ -106,12 +247,11 @@ func ProcessFields(allStructFields []Field) ([]Field, []Tags, error) {
 		} else {
 			if anyOptional {
 				msg := fmt.Sprintf("must be optional because preceding field %q is optional", firstOptionalName)
-				return nil, nil, TagError{Field: name, Err: msg}
+				return TagError{Field: name, Err: msg}
 			}
 		}
 	}
-
-	return fields, tags, nil
+	return nil
 }
 
 func parseTag(field Field, lastPublic int) (Tags, error) {
@@ -130,14 +270,30 @@ func parseTag(field Field, lastPublic int) (Tags, error) {
 			}
 			switch t {
 			case "nil":
-				ts.NilKind = field.Type.DeaultNilValue()
+				ts.NilKind = field.Type.Elem.DeaultNilValue()
 			case "nilString":
 				ts.NilKind = NilKindString
 			case "nilList":
 				ts.NilKind = NilKindList
 			}
+		case "map":
+			ts.Map = true
+			if field.Type.Kind != reflect.Map {
+				return ts, TagError{Field: name, Tag: t, Err: "field is not a map"}
+			}
+		case "inline":
+			ts.Inline = true
+			if !field.Embedded || field.Type.Kind != reflect.Struct {
+				return ts, TagError{Field: name, Tag: t, Err: "field is not an embedded struct"}
+			}
+			if ts.Optional {
+				return ts, TagError{Field: name, Tag: t, Err: `also has "optional" tag`}
+			}
 		case "optional":
 			ts.Optional = true
+			if ts.Inline {
+				return ts, TagError{Field: name, Tag: t, Err: `also has "inline" tag`}
+			}
 			if ts.Tail {
 				return ts, TagError{Field: name, Tag: t, Err: `also has "tail" tag`}
 			}
@@ -154,6 +310,24 @@ func parseTag(field Field, lastPublic int) (Tags, error) {
 				return ts, TagError{Field: name, Tag: t, Err: "field type is not slice"}
 			}
 		default:
+			if strings.HasPrefix(t, "typed=") {
+				ts.Typed = strings.TrimPrefix(t, "typed=")
+				if ts.Typed == "" {
+					return ts, TagError{Field: name, Tag: t, Err: "missing interface name"}
+				}
+				if field.Type.Kind != reflect.Interface {
+					return ts, TagError{Field: name, Tag: t, Err: "field is not an interface"}
+				}
+				continue
+			}
+			if strings.HasPrefix(t, "pos=") {
+				pos, err := strconv.Atoi(strings.TrimPrefix(t, "pos="))
+				if err != nil || pos < 0 {
+					return ts, TagError{Field: name, Tag: t, Err: "invalid position"}
+				}
+				ts.Position, ts.HasPosition = pos, true
+				continue
+			}
 			return ts, TagError{Field: name, Tag: t, Err: "unknown tag"}
 		}
 	}
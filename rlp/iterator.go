@@ -0,0 +1,196 @@
+package rlp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrListSize is returned by ListWriter when the elements written do not add
+// up to the content size given to NewListWriter.
+var ErrListSize = errors.New("rlp: list content does not match declared size")
+
+// ListIterator iterates over the elements of an encoded list held in memory.
+type ListIterator struct {
+	data []byte
+	next []byte
+	err  error
+}
+
+// NewListIterator creates an iterator for the list encoded in data.
+func NewListIterator(data RawValue) (*ListIterator, error) {
+	k, t, c, err := readKind(data)
+	if err != nil {
+		return nil, err
+	}
+	if k != List {
+		return nil, ErrExpectedList
+	}
+	it := &ListIterator{
+		data: data[t : t+c],
+	}
+	return it, nil
+}
+
+// Next moves the iterator to the next element. It returns false at the end
+// of the list or when the list content is malformed, check Err to tell them
+// apart.
+func (it *ListIterator) Next() bool {
+	if len(it.data) == 0 || it.err != nil {
+		return false
+	}
+	_, t, c, err := readKind(it.data)
+	if err != nil {
+		it.err = err
+		it.next = nil
+		return false
+	}
+	it.next = it.data[:t+c]
+	it.data = it.data[t+c:]
+	return true
+}
+
+// Value returns the current element. It is a subslice of the input.
+func (it *ListIterator) Value() RawValue {
+	return it.next
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// StreamIterator iterates over the elements of a list read from a Stream.
+// Only the current element is held in memory, which makes it possible to
+// process lists larger than the available memory.
+type StreamIterator struct {
+	s    *Stream
+	next RawValue
+	err  error
+	done bool
+}
+
+// NewStreamIterator starts reading a list from s and returns an iterator for
+// its elements. When the iterator is exhausted, s is positioned after the
+// end of the list.
+func NewStreamIterator(s *Stream) (*StreamIterator, error) {
+	if _, err := s.List(); err != nil {
+		return nil, err
+	}
+	return &StreamIterator{s: s}, nil
+}
+
+// Next reads the next element. It returns false at the end of the list or
+// when an error occurred, check Err to tell them apart.
+func (it *StreamIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.s.MoreDataInList() {
+		it.finish(it.s.ListEnd())
+		return false
+	}
+	it.next, it.err = it.s.Raw()
+	if it.err != nil {
+		it.finish(it.err)
+		return false
+	}
+	return true
+}
+
+func (it *StreamIterator) finish(err error) {
+	it.done = true
+	it.next = nil
+	it.err = err
+}
+
+// Value returns the current element. The iterator does not keep a reference
+// to it, the slice may be retained by the caller.
+func (it *StreamIterator) Value() RawValue {
+	return it.next
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *StreamIterator) Err() error {
+	return it.err
+}
+
+// ListWriter writes a list to an io.Writer one element at a time. The list
+// header is written first, so the content size must be known in advance,
+// e.g. by adding up the results of EncodedSize for all elements. Each element
+// is written to the underlying writer as soon as it has been encoded.
+type ListWriter struct {
+	w         io.Writer
+	remaining uint64
+	err       error
+}
+
+// NewListWriter writes the header of a list with the given content size to w
+// and returns a writer for its elements.
+func NewListWriter(w io.Writer, contentSize uint64) (*ListWriter, error) {
+	head := AppendListHeader(make([]byte, 0, 9), contentSize)
+	if _, err := w.Write(head); err != nil {
+		return nil, err
+	}
+	return &ListWriter{w: w, remaining: contentSize}, nil
+}
+
+// Encode encodes val as the next element of the list.
+func (lw *ListWriter) Encode(val interface{}) error {
+	if lw.err != nil {
+		return lw.err
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	if err := buf.encode(val); err != nil {
+		return err
+	}
+	if err := lw.reserve(uint64(buf.size())); err != nil {
+		return err
+	}
+	return lw.fail(buf.writeTo(lw.w))
+}
+
+// WriteRaw writes v, which must be a single encoded value, as the next
+// element of the list.
+func (lw *ListWriter) WriteRaw(v RawValue) error {
+	if lw.err != nil {
+		return lw.err
+	}
+	if err := lw.reserve(uint64(len(v))); err != nil {
+		return err
+	}
+	_, err := lw.w.Write(v)
+	return lw.fail(err)
+}
+
+// Close checks that the list is complete. It does not close the underlying
+// writer.
+func (lw *ListWriter) Close() error {
+	if lw.err != nil {
+		return lw.err
+	}
+	if lw.remaining != 0 {
+		return lw.fail(fmt.Errorf("%w: %d bytes missing", ErrListSize, lw.remaining))
+	}
+	return nil
+}
+
+// reserve accounts for an element of the given size. Nothing has been
+// written when it fails, so the writer can still be used.
+func (lw *ListWriter) reserve(size uint64) error {
+	if size > lw.remaining {
+		return fmt.Errorf("%w: element of %d bytes exceeds remaining %d bytes", ErrListSize, size, lw.remaining)
+	}
+	lw.remaining -= size
+	return nil
+}
+
+// fail records a write error. The list is incomplete after that and all
+// further operations return the error.
+func (lw *ListWriter) fail(err error) error {
+	if err != nil {
+		lw.err = err
+	}
+	return err
+}
@@ -0,0 +1,211 @@
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+var iteratorTestElems = []interface{}{uint64(1), "hello", []uint{1, 2, 3}, bytes.Repeat([]byte{1}, 100)}
+
+// iteratorTestInput returns the encoding of iteratorTestElems and the
+// encodings of its elements.
+func iteratorTestInput(t *testing.T) (RawValue, []RawValue) {
+	enc, err := EncodeToBytes(iteratorTestElems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elems := make([]RawValue, len(iteratorTestElems))
+	for i, e := range iteratorTestElems {
+		if elems[i], err = EncodeToBytes(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return enc, elems
+}
+
+func TestListIterator(t *testing.T) {
+	enc, want := iteratorTestInput(t)
+	it, err := NewListIterator(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []RawValue
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong elements:\ngot  %x\nwant %x", got, want)
+	}
+
+	if _, err := NewListIterator(unhex("83646f67")); err != ErrExpectedList {
+		t.Errorf("string input: got error %v, want %v", err, ErrExpectedList)
+	}
+	// The second element is truncated.
+	it, err = NewListIterator(unhex("C3018201"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !it.Next() || !bytes.Equal(it.Value(), []byte{1}) {
+		t.Fatal("first element not returned")
+	}
+	if it.Next() {
+		t.Fatal("Next returned true for truncated element")
+	}
+	if it.Err() == nil {
+		t.Fatal("no error for truncated element")
+	}
+}
+
+func TestStreamIterator(t *testing.T) {
+	enc, want := iteratorTestInput(t)
+	// The stream continues after the list.
+	input := append(enc, 0x05)
+	s := NewStream(bytes.NewReader(input), 0)
+	it, err := NewStreamIterator(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []RawValue
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after end of list")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong elements:\ngot  %x\nwant %x", got, want)
+	}
+	if v, err := s.Uint64(); err != nil || v != 5 {
+		t.Fatalf("value after list: got %d, %v; want 5", v, err)
+	}
+
+	s = NewStream(bytes.NewReader(unhex("83646f67")), 0)
+	if _, err := NewStreamIterator(s); err != ErrExpectedList {
+		t.Errorf("string input: got error %v, want %v", err, ErrExpectedList)
+	}
+}
+
+func TestStreamIteratorTruncated(t *testing.T) {
+	// Hide the bytes.Reader so the stream has no input limit and only
+	// notices the truncation while reading the element.
+	r := struct{ io.Reader }{bytes.NewReader(unhex("C3018201"))}
+	it, err := NewStreamIterator(NewStream(r, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !it.Next() || !bytes.Equal(it.Value(), []byte{1}) {
+		t.Fatal("first element not returned")
+	}
+	if it.Next() {
+		t.Fatal("Next returned true for truncated element")
+	}
+	if it.Err() == nil {
+		t.Fatal("no error for truncated element")
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after error")
+	}
+}
+
+func TestListWriter(t *testing.T) {
+	enc, elems := iteratorTestInput(t)
+	var size uint64
+	for _, e := range elems {
+		size += uint64(len(e))
+	}
+
+	// Mix Encode and WriteRaw.
+	var out bytes.Buffer
+	lw, err := NewListWriter(&out, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range iteratorTestElems {
+		if i%2 == 0 {
+			err = lw.Encode(e)
+		} else {
+			err = lw.WriteRaw(elems[i])
+		}
+		if err != nil {
+			t.Fatalf("element %d: %v", i, err)
+		}
+	}
+	if err := lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), enc) {
+		t.Fatalf("wrong output:\ngot  %x\nwant %x", out.Bytes(), enc)
+	}
+}
+
+func TestListWriterSize(t *testing.T) {
+	// Missing elements.
+	lw, _ := NewListWriter(new(bytes.Buffer), 3)
+	if err := lw.Encode(uint(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.Close(); !errors.Is(err, ErrListSize) {
+		t.Errorf("Close: got error %v, want %v", err, ErrListSize)
+	}
+
+	// Too many elements. The element is not written and the writer is
+	// still usable.
+	var out bytes.Buffer
+	lw, _ = NewListWriter(&out, 2)
+	if err := lw.Encode("abc"); !errors.Is(err, ErrListSize) {
+		t.Errorf("Encode: got error %v, want %v", err, ErrListSize)
+	}
+	if err := lw.WriteRaw(unhex("820304")); !errors.Is(err, ErrListSize) {
+		t.Errorf("WriteRaw: got error %v, want %v", err, ErrListSize)
+	}
+	if err := lw.Encode([]uint{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex("C2C101"); !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("wrong output %x, want %x", out.Bytes(), want)
+	}
+}
+
+type failWriter struct{ n int }
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failWriter) Write(b []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errWriteFailed
+	}
+	w.n--
+	return len(b), nil
+}
+
+func TestListWriterWriteError(t *testing.T) {
+	if _, err := NewListWriter(&failWriter{}, 1); err != errWriteFailed {
+		t.Fatalf("header: got error %v, want %v", err, errWriteFailed)
+	}
+	lw, err := NewListWriter(&failWriter{n: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.Encode(uint(1)); err != errWriteFailed {
+		t.Fatalf("Encode: got error %v, want %v", err, errWriteFailed)
+	}
+	// The error sticks.
+	if err := lw.WriteRaw(unhex("01")); err != errWriteFailed {
+		t.Errorf("WriteRaw: got error %v, want %v", err, errWriteFailed)
+	}
+	if err := lw.Close(); err != errWriteFailed {
+		t.Errorf("Close: got error %v, want %v", err, errWriteFailed)
+	}
+}
@@ -0,0 +1,144 @@
+package rlp
+
+import (
+	"awesomeProject/rlp/internal/rlpstruct"
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Maps can be encoded when the struct field holding them has the tag
+// rlp:"map". A map is encoded as a list of [key, value] pairs, sorted by the
+// encoding of the key. This makes the encoding canonical: the decoder
+// rejects input where the keys are not in this order or appear twice. An
+// empty or nil map is encoded as an empty list.
+
+type mapEntry struct {
+	enc []byte // encoded key
+	key reflect.Value
+	val reflect.Value
+}
+
+func makeMapWriter(typ reflect.Type) (writer, error) {
+	keyinfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tags{})
+	if keyinfo.writerErr != nil {
+		return nil, keyinfo.writerErr
+	}
+	valinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	if valinfo.writerErr != nil {
+		return nil, valinfo.writerErr
+	}
+	writer := func(val reflect.Value, w *encBuffer) error {
+		if val.Len() == 0 {
+			w.str = append(w.str, 0xC0)
+			return nil
+		}
+
+		// Encode the keys first, the encodings determine the order.
+		keybuf := getEncBuffer()
+		defer encBufferPool.Put(keybuf)
+		entries := make([]mapEntry, 0, val.Len())
+		for it := val.MapRange(); it.Next(); {
+			keybuf.reset()
+			if err := keyinfo.writer(it.Key(), keybuf); err != nil {
+				return addEncodeContext(err, mapKeyContext(it.Key()))
+			}
+			entries = append(entries, mapEntry{enc: keybuf.makeBytes(), key: it.Key(), val: it.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].enc, entries[j].enc) < 0
+		})
+
+		offset := w.list()
+		for i, e := range entries {
+			if i > 0 && bytes.Equal(e.enc, entries[i-1].enc) {
+				return addEncodeContext(fmt.Errorf("rlp: map keys %v and %v have the same encoding", entries[i-1].key, e.key), mapKeyContext(e.key))
+			}
+			pair := w.list()
+			w.str = append(w.str, e.enc...)
+			if err := valinfo.writer(e.val, w); err != nil {
+				return addEncodeContext(err, mapKeyContext(e.key))
+			}
+			w.endlist(pair)
+		}
+		w.endlist(offset)
+		return nil
+	}
+	return writer, nil
+}
+
+func makeMapDecoder(typ reflect.Type) (decoder, error) {
+	keyinfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tags{})
+	if keyinfo.decoderErr != nil {
+		return nil, keyinfo.decoderErr
+	}
+	valinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	if valinfo.decoderErr != nil {
+		return nil, valinfo.decoderErr
+	}
+	dec := func(s *Stream, val reflect.Value) error {
+		if _, err := s.List(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		keystream := streamPool.Get().(*Stream)
+		defer streamPool.Put(keystream)
+
+		m := reflect.MakeMap(typ)
+		var prev []byte
+		for i := 0; s.MoreDataInList(); i++ {
+			ctx := fmt.Sprint("[", i, "]")
+			if _, err := s.List(); err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			enc, err := s.Raw()
+			if err != nil {
+				return addErrorContext(wrapStreamError(err, typ.Key()), ctx)
+			}
+			if prev != nil {
+				switch c := bytes.Compare(prev, enc); {
+				case c == 0:
+					return addErrorContext(&decodeError{msg: "duplicate map key", typ: typ}, ctx)
+				case c > 0:
+					return addErrorContext(&decodeError{msg: "map keys not in canonical order", typ: typ}, ctx)
+				}
+			}
+			prev = enc
+
+			key := reflect.New(typ.Key()).Elem()
+			keystream.Reset(bytes.NewReader(enc), uint64(len(enc)))
+			keystream.limits = s.limits
+			keystream.depth = s.depth + len(s.stack)
+			keystream.ctx = s.ctx
+			if err := keyinfo.decoder(keystream, key); err != nil {
+				return addErrorContext(err, ctx)
+			}
+			if m.MapIndex(key).IsValid() {
+				return addErrorContext(&decodeError{msg: "duplicate map key", typ: typ}, ctx)
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := valinfo.decoder(s, elem); err != nil {
+				return addErrorContext(err, ctx)
+			}
+			if err := s.ListEnd(); err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			m.SetMapIndex(key, elem)
+		}
+		if err := s.ListEnd(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		val.Set(m)
+		return nil
+	}
+	return dec, nil
+}
+
+// mapKeyContext formats a map key for EncodeError paths.
+func mapKeyContext(key reflect.Value) string {
+	k := key.Kind()
+	if (k == reflect.Array || k == reflect.Slice) && isByte(key.Type().Elem()) {
+		return fmt.Sprintf("[%#x]", key)
+	}
+	return fmt.Sprintf("[%v]", key)
+}
@@ -0,0 +1,145 @@
+package rlp
+
+import (
+	"awesomeProject/common"
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type mapTestStruct struct {
+	Accounts map[common.Address]*big.Int `rlp:"map"`
+	Tags     map[string]uint             `rlp:"map"`
+	Empty    map[uint]uint               `rlp:"map"`
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	val := mapTestStruct{
+		Accounts: map[common.Address]*big.Int{{1}: big.NewInt(5), {0, 2}: big.NewInt(1000)},
+		Tags:     map[string]uint{"zeta": 1, "a": 2, "bb": 3, "": 4},
+	}
+	// Pairs are sorted by key encoding, Empty is encoded as an empty list.
+	want := unhex("F845" +
+		"F0" +
+		"D894" + "0002000000000000000000000000000000000000" + "8203E8" +
+		"D694" + "0100000000000000000000000000000000000000" + "05" +
+		"D2" + "C26102" + "C28004" + "C482626203" + "C6847A65746101" +
+		"C0")
+	// Map iteration order is random, encode a few times.
+	for i := 0; i < 10; i++ {
+		enc, err := EncodeToBytes(&val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(enc, want) {
+			t.Fatalf("wrong encoding\ngot  %x\nwant %x", enc, want)
+		}
+	}
+
+	var dec mapTestStruct
+	if err := DecodeBytes(want, &dec); err != nil {
+		t.Fatal(err)
+	}
+	// Nil maps are decoded as empty maps.
+	val.Empty = map[uint]uint{}
+	if !reflect.DeepEqual(dec, val) {
+		t.Fatalf("decoded %+v, want %+v", dec, val)
+	}
+}
+
+func TestMapDecodeErrors(t *testing.T) {
+	type mapTest struct {
+		M map[uint]uint `rlp:"map"`
+	}
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"C7C6C20105C20206", ""},
+		{"C7C6C20205C20106", "rlp: map keys not in canonical order for map[uint]uint, decoding into rlp.mapTest.M[1]"},
+		{"C7C6C20105C20106", "rlp: duplicate map key for map[uint]uint, decoding into rlp.mapTest.M[1]"},
+		{"C5C4C3010507", "rlp: input list has too many elements for map[uint]uint, decoding into rlp.mapTest.M[0]"},
+		{"C3C2C101", "rlp: too few elements for rlp.mapTest"},
+		{"C2C105", "rlp: expected input list for map[uint]uint, decoding into rlp.mapTest.M[0]"},
+		{"C101", "rlp: expected input list for map[uint]uint, decoding into rlp.mapTest.M"},
+	}
+	for _, test := range tests {
+		var dec mapTest
+		err := DecodeBytes(unhex(test.input), &dec)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.input, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", test.input)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: wrong error\ngot:  %v\nwant: %v", test.input, err, test.err)
+		}
+	}
+}
+
+func TestMapKeyLimits(t *testing.T) {
+	type mapTest struct {
+		M map[[3]uint]uint `rlp:"map"`
+	}
+	// The key list is nested in the struct, the map and the pair list.
+	input := unhex("C7C6C5C301020305")
+	decode := func(l Limits) error {
+		s := NewStream(bytes.NewReader(input), 0)
+		s.SetLimits(l)
+		var dec mapTest
+		return s.Decode(&dec)
+	}
+
+	if err := decode(Limits{MaxDepth: 4, MaxElems: 3}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := decode(Limits{MaxDepth: 3}); !errors.Is(err, ErrListTooDeep) {
+		t.Errorf("MaxDepth 3: got error %v, want %v", err, ErrListTooDeep)
+	}
+	if err := decode(Limits{MaxElems: 2}); !errors.Is(err, ErrTooManyElems) {
+		t.Errorf("MaxElems 2: got error %v, want %v", err, ErrTooManyElems)
+	}
+}
+
+func TestMapTypeErrors(t *testing.T) {
+	type untagged struct {
+		M map[string]uint
+	}
+	type notMap struct {
+		M []uint `rlp:"map"`
+	}
+	tests := []struct {
+		val interface{}
+		err string
+	}{
+		{&untagged{}, "rlp: type map[string]uint is not RLP-serializable (struct field rlp.untagged.M)"},
+		{&notMap{}, `rlp: invalid struct tag "map" for rlp.notMap.M (field is not a map)`},
+	}
+	for _, test := range tests {
+		_, err := EncodeToBytes(test.val)
+		if err == nil {
+			t.Errorf("%T: expected error", test.val)
+		} else if err.Error() != test.err {
+			t.Errorf("%T: wrong error\ngot:  %v\nwant: %v", test.val, err, test.err)
+		}
+	}
+}
+
+func TestMapEncodeErrorPath(t *testing.T) {
+	type negative struct {
+		M map[common.Address]*big.Int `rlp:"map"`
+	}
+	_, err := EncodeToBytes(&negative{M: map[common.Address]*big.Int{{9}: big.NewInt(-1)}})
+	var encErr *EncodeError
+	if !errors.As(err, &encErr) {
+		t.Fatalf("got error %v, want *EncodeError", err)
+	}
+	if want := ".M[0x0900000000000000000000000000000000000000]"; encErr.Path != want {
+		t.Errorf("wrong path %q, want %q", encErr.Path, want)
+	}
+}
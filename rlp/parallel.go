@@ -0,0 +1,127 @@
+package rlp
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// EncodeOptions are optional settings of the encoder.
+//
+// The options apply to values encoded by reflection. Types with an EncodeRLP
+// method encode their content themselves and are not affected by the options.
+// This includes all types with encoders generated by rlpgen, such as
+// types.Block and types.Header.
+type EncodeOptions struct {
+	// ParallelThreshold is the minimum number of elements of a slice or
+	// array for encoding its elements in parallel. Zero disables parallel
+	// encoding.
+	//
+	// Only the outermost qualifying lists are encoded in parallel, lists
+	// within their elements are encoded sequentially. The output is the same
+	// as without parallel encoding. EncodeRLP methods of the elements are
+	// called concurrently.
+	ParallelThreshold int
+
+	// Workers is the number of goroutines encoding the elements of one list.
+	// Zero means runtime.GOMAXPROCS(0), so lists are encoded sequentially
+	// when GOMAXPROCS is 1.
+	Workers int
+}
+
+// workers returns the number of goroutines to use for a list of n elements,
+// or zero if it should be encoded sequentially.
+func (o *EncodeOptions) workers(n int) int {
+	if o.ParallelThreshold <= 0 || n < o.ParallelThreshold {
+		return 0
+	}
+	workers := o.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 2 {
+		return 0
+	}
+	return workers
+}
+
+// EncodeWithOptions is like Encode, but applies opts.
+func EncodeWithOptions(w io.Writer, val interface{}, opts EncodeOptions) error {
+	if buf := encBufferFromWriter(w); buf != nil {
+		prev := buf.opts
+		buf.opts = opts
+		defer func() { buf.opts = prev }()
+		return buf.encode(val)
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.opts = opts
+	if err := buf.encode(val); err != nil {
+		return err
+	}
+	return buf.writeTo(w)
+}
+
+// EncodeToBytesWithOptions is like EncodeToBytes, but applies opts.
+func EncodeToBytesWithOptions(val interface{}, opts EncodeOptions) ([]byte, error) {
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.opts = opts
+	if err := buf.encode(val); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
+// writeElemsParallel writes the elements of value to w, like a sequential
+// loop over the elements would. The elements are split into one contiguous
+// chunk per worker, and every chunk is encoded into its own buffer.
+func writeElemsParallel(value reflect.Value, w *encBuffer, elemwriter writer, workers int) error {
+	var (
+		n    = value.Len()
+		bufs = make([]*encBuffer, workers)
+		errs = make([]error, workers)
+		wg   sync.WaitGroup
+	)
+	for k := range bufs {
+		bufs[k] = getEncBuffer()
+		bufs[k].ctx = w.ctx
+		start, end := k*n/workers, (k+1)*n/workers
+		wg.Add(1)
+		go func(buf *encBuffer, errp *error) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if err := buf.checkContext(); err != nil {
+					*errp = err
+					return
+				}
+				if err := elemwriter(value.Index(i), buf); err != nil {
+					*errp = addEncodeContext(err, fmt.Sprint("[", i, "]"))
+					return
+				}
+			}
+		}(bufs[k], &errs[k])
+	}
+	wg.Wait()
+
+	defer func() {
+		for _, buf := range bufs {
+			encBufferPool.Put(buf)
+		}
+	}()
+	// The first error is the one the sequential loop would return.
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	for _, buf := range bufs {
+		w.appendBuffer(buf)
+	}
+	return nil
+}
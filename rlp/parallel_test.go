@@ -0,0 +1,136 @@
+package rlp_test
+
+import (
+	"awesomeProject/common"
+	"awesomeProject/core/types"
+	"awesomeProject/rlp"
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// parallelTestValues returns large lists. types.Block can't be used here: it is
+// encoded by the generated encoder of its extblock type, which does not use
+// EncodeOptions. types.Extblock has the same layout, but no encoder methods.
+func parallelTestValues() []struct {
+	name string
+	val  interface{}
+} {
+	nested := make([][]uint, 1000)
+	for i := range nested {
+		nested[i] = make([]uint, i%7)
+		for j := range nested[i] {
+			nested[i][j] = uint(i * j)
+		}
+	}
+	return []struct {
+		name string
+		val  interface{}
+	}{
+		// Transactions have no encodable fields in this tree, each one
+		// encodes as an empty list.
+		{"empty-txs-1000", types.Newextblock(types.Newblock())},
+		{"receipts-1000", makeReceipts(1000)},
+		{"nested-1000", nested},
+		{"array", [5]string{"a", "b", "c", "d", "e"}},
+	}
+}
+
+func makeReceipts(n int) types.Receipts {
+	receipts := make(types.Receipts, n)
+	for i := range receipts {
+		receipts[i] = &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*types.Log{{
+				Address: common.Address{byte(i)},
+				Topics:  []common.Hash{common.BytesToHash([]byte{1}), common.BytesToHash([]byte{byte(i)})},
+				Data:    make([]byte, 64),
+			}},
+			GasUsed:     21000,
+			BlockNumber: big.NewInt(int64(i)),
+		}
+	}
+	return receipts
+}
+
+func TestEncodeParallel(t *testing.T) {
+	for _, test := range parallelTestValues() {
+		want, err := rlp.EncodeToBytes(test.val)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for _, opts := range []rlp.EncodeOptions{
+			{ParallelThreshold: 1, Workers: 2},
+			{ParallelThreshold: 2, Workers: 3},
+			{ParallelThreshold: 4, Workers: 16},
+			{ParallelThreshold: 256},
+		} {
+			enc, err := rlp.EncodeToBytesWithOptions(test.val, opts)
+			if err != nil {
+				t.Fatalf("%s %+v: %v", test.name, opts, err)
+			}
+			if !bytes.Equal(enc, want) {
+				t.Errorf("%s %+v: output differs from sequential encoding", test.name, opts)
+			}
+			var buf bytes.Buffer
+			if err := rlp.EncodeWithOptions(&buf, test.val, opts); err != nil {
+				t.Fatalf("%s %+v: %v", test.name, opts, err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("%s %+v: EncodeWithOptions output differs from sequential encoding", test.name, opts)
+			}
+		}
+	}
+}
+
+func TestEncodeParallelError(t *testing.T) {
+	ints := make([]*big.Int, 1000)
+	for i := range ints {
+		ints[i] = big.NewInt(int64(i))
+	}
+	ints[300] = big.NewInt(-1)
+	ints[700] = big.NewInt(-1)
+
+	_, want := rlp.EncodeToBytes(ints)
+	for _, workers := range []int{2, 3, 8} {
+		opts := rlp.EncodeOptions{ParallelThreshold: 1, Workers: workers}
+		_, err := rlp.EncodeToBytesWithOptions(ints, opts)
+		var encErr *rlp.EncodeError
+		if !errors.As(err, &encErr) {
+			t.Fatalf("workers %d: got error %v, want *EncodeError", workers, err)
+		}
+		// The error of the first failing element is returned, as in
+		// sequential encoding.
+		if err.Error() != want.Error() {
+			t.Errorf("workers %d: got error %q, want %q", workers, err, want)
+		}
+	}
+}
+
+// BenchmarkEncodeParallel compares sequential and parallel encoding. Workers
+// is set, so the parallel case runs in parallel regardless of GOMAXPROCS. Use
+// the -cpu flag to set the number of threads running them.
+func BenchmarkEncodeParallel(b *testing.B) {
+	opts := rlp.EncodeOptions{ParallelThreshold: 256, Workers: 4}
+	for _, test := range parallelTestValues() {
+		val := test.val
+		b.Run(test.name+"/sequential", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := rlp.EncodeToBytes(val); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(test.name+"/parallel", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := rlp.EncodeToBytesWithOptions(val, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
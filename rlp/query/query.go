@@ -0,0 +1,176 @@
+// Package query extracts values from RLP-encoded data without Go types.
+//
+// A query is a path made of the following steps:
+//
+//	[N]   selects element N (counting from zero) of a list
+//	[*]   selects every element of a list
+//	.len  yields the number of elements of a list or the length of a string
+//
+// For example, [0][8] selects the ninth field of the first list element,
+// [1][*][4] selects the fifth field of every element of the second list
+// element and [0].len counts the elements of the first element.
+//
+// The result of a query is a list of RLP values. The .len step produces
+// its count as an encoded integer.
+package query
+
+import (
+	"awesomeProject/rlp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepIndex stepKind = iota
+	stepAll
+	stepLen
+)
+
+type step struct {
+	kind  stepKind
+	index int
+}
+
+func (s step) String() string {
+	switch s.kind {
+	case stepIndex:
+		return "[" + strconv.Itoa(s.index) + "]"
+	case stepAll:
+		return "[*]"
+	default:
+		return ".len"
+	}
+}
+
+// Path is a compiled query.
+type Path struct {
+	steps []step
+}
+
+// Compile parses a query path.
+func Compile(path string) (*Path, error) {
+	var (
+		p   = new(Path)
+		pos = 0
+	)
+	for pos < len(path) {
+		switch {
+		case path[pos] == '[':
+			end := strings.IndexByte(path[pos:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("query: missing ']' at offset %d", pos)
+			}
+			arg := path[pos+1 : pos+end]
+			if arg == "*" {
+				p.steps = append(p.steps, step{kind: stepAll})
+			} else {
+				i, err := strconv.ParseUint(arg, 10, 31)
+				if err != nil {
+					return nil, fmt.Errorf("query: invalid index %q at offset %d", arg, pos)
+				}
+				p.steps = append(p.steps, step{kind: stepIndex, index: int(i)})
+			}
+			pos += end + 1
+		case strings.HasPrefix(path[pos:], ".len"):
+			p.steps = append(p.steps, step{kind: stepLen})
+			pos += len(".len")
+		default:
+			return nil, fmt.Errorf("query: unexpected %q at offset %d", path[pos], pos)
+		}
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile, but panics if the path cannot be parsed.
+func MustCompile(path string) *Path {
+	p, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the path in query syntax.
+func (p *Path) String() string {
+	var sb strings.Builder
+	for _, s := range p.steps {
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// Eval runs the query against b, which must hold exactly one RLP value.
+// The returned values are subslices of b, except for the results of .len.
+func (p *Path) Eval(b []byte) ([]rlp.RawValue, error) {
+	_, _, rest, err := rlp.Split(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, rlp.ErrMoreThanOneValue
+	}
+	return p.eval(nil, b, 0)
+}
+
+func (p *Path) eval(out []rlp.RawValue, v []byte, depth int) ([]rlp.RawValue, error) {
+	if depth == len(p.steps) {
+		return append(out, v), nil
+	}
+	s := p.steps[depth]
+	k, content, _, err := rlp.Split(v)
+	if err != nil {
+		return nil, p.error(depth, err)
+	}
+
+	if s.kind == stepLen {
+		n := len(content)
+		if k == rlp.List {
+			if n, err = rlp.CountValues(content); err != nil {
+				return nil, p.error(depth, err)
+			}
+		}
+		return p.eval(out, rlp.AppendUint64(nil, uint64(n)), depth+1)
+	}
+
+	if k != rlp.List {
+		return nil, p.error(depth, rlp.ErrExpectedList)
+	}
+	for i := 0; len(content) > 0; i++ {
+		_, _, rest, err := rlp.Split(content)
+		if err != nil {
+			return nil, p.error(depth, err)
+		}
+		elem := content[:len(content)-len(rest)]
+		content = rest
+		switch {
+		case s.kind == stepAll:
+			if out, err = p.eval(out, elem, depth+1); err != nil {
+				return nil, err
+			}
+		case i == s.index:
+			return p.eval(out, elem, depth+1)
+		}
+	}
+	if s.kind == stepIndex {
+		return nil, p.error(depth, fmt.Errorf("index %d out of range", s.index))
+	}
+	return out, nil
+}
+
+// error annotates err with the path leading up to the failed step.
+func (p *Path) error(depth int, err error) error {
+	prefix := (&Path{steps: p.steps[:depth+1]}).String()
+	return fmt.Errorf("query: %s: %w", prefix, err)
+}
+
+// Query compiles path and runs it against b.
+func Query(b []byte, path string) ([]rlp.RawValue, error) {
+	p, err := Compile(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Eval(b)
+}
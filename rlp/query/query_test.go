@@ -0,0 +1,154 @@
+package query
+
+import (
+	"awesomeProject/rlp"
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{path: ""},
+		{path: "[0]"},
+		{path: "[0][8]"},
+		{path: "[1][*][4]"},
+		{path: "[0].len"},
+		{path: ".len.len"},
+		{path: "[2147483647]"},
+		{path: "[0", err: "query: missing ']' at offset 0"},
+		{path: "[0][", err: "query: missing ']' at offset 3"},
+		{path: "[]", err: `query: invalid index "" at offset 0`},
+		{path: "[-1]", err: `query: invalid index "-1" at offset 0`},
+		{path: "[0][x]", err: `query: invalid index "x" at offset 3`},
+		{path: "[2147483648]", err: `query: invalid index "2147483648" at offset 0`},
+		{path: "[0].length", err: `query: unexpected 'g' at offset 7`},
+		{path: "0", err: `query: unexpected '0' at offset 0`},
+		{path: "[0] [1]", err: `query: unexpected ' ' at offset 3`},
+	}
+	for _, test := range tests {
+		p, err := Compile(test.path)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: got error %v, want %s", test.path, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.path, err)
+			continue
+		}
+		if s := p.String(); s != test.path {
+			t.Errorf("%q: String returned %q", test.path, s)
+		}
+	}
+}
+
+func mustEncode(v interface{}) []byte {
+	enc, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+func TestEval(t *testing.T) {
+	input := mustEncode([]interface{}{
+		"dog",
+		[]uint{1, 2, 300},
+		[][]string{{"a", "b"}, {"c", "d", "e"}},
+		"",
+	})
+	tests := []struct {
+		path string
+		want []interface{} // encoded to get the expected values
+	}{
+		{path: "", want: []interface{}{rlp.RawValue(input)}},
+		{path: "[0]", want: []interface{}{"dog"}},
+		{path: "[1][2]", want: []interface{}{uint(300)}},
+		{path: "[2][1][2]", want: []interface{}{"e"}},
+		{path: "[2][*][0]", want: []interface{}{"a", "c"}},
+		{path: "[2][*]", want: []interface{}{[]string{"a", "b"}, []string{"c", "d", "e"}}},
+		{path: "[2][*][*]", want: []interface{}{"a", "b", "c", "d", "e"}},
+		{path: ".len", want: []interface{}{uint(4)}},
+		{path: "[0].len", want: []interface{}{uint(3)}},
+		{path: "[3].len", want: []interface{}{uint(0)}},
+		{path: "[1][0].len", want: []interface{}{uint(1)}},
+		{path: "[2][*].len", want: []interface{}{uint(2), uint(3)}},
+		{path: "[1][2].len", want: []interface{}{uint(2)}},
+		{path: ".len.len", want: []interface{}{uint(1)}},
+	}
+	for _, test := range tests {
+		got, err := Query(input, test.path)
+		if err != nil {
+			t.Errorf("%q: error: %v", test.path, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%q: got %d results %x, want %d", test.path, len(got), got, len(test.want))
+			continue
+		}
+		for i, w := range test.want {
+			if want := mustEncode(w); !bytes.Equal(got[i], want) {
+				t.Errorf("%q: result %d is %x, want %x", test.path, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	input := mustEncode([]interface{}{"dog", []uint{1, 2}, []interface{}{[]uint{1}, "x"}})
+	tests := []struct {
+		input []byte
+		path  string
+		err   string
+		is    error
+	}{
+		{input: input, path: "[3]", err: "query: [3]: index 3 out of range"},
+		{input: input, path: "[1][5]", err: "query: [1][5]: index 5 out of range"},
+		{input: input, path: "[0][0]", err: "query: [0][0]: rlp: expected List", is: rlp.ErrExpectedList},
+		{input: input, path: "[2][*][0]", err: "query: [2][*][0]: rlp: expected List", is: rlp.ErrExpectedList},
+		{input: append(input, 0x80), path: "[0]", is: rlp.ErrMoreThanOneValue},
+		{input: []byte{0xC3, 0x81, 0x05, 0x80}, path: "[0]", err: "query: [0]: rlp: non-canonical size information", is: rlp.ErrCanonSize},
+		{input: []byte{0xC3, 0x81, 0x05, 0x80}, path: ".len", is: rlp.ErrCanonSize},
+		{input: []byte{0xC2, 0x01}, path: "[0]", is: rlp.ErrValueTooLarge},
+		{input: nil, path: "[0]"},
+	}
+	for _, test := range tests {
+		_, err := Query(test.input, test.path)
+		if err == nil {
+			t.Errorf("%x %q: expected error", test.input, test.path)
+			continue
+		}
+		if test.err != "" && err.Error() != test.err {
+			t.Errorf("%x %q: wrong error %q, want %q", test.input, test.path, err, test.err)
+		}
+		if test.is != nil && !errors.Is(err, test.is) {
+			t.Errorf("%x %q: error %v is not %v", test.input, test.path, err, test.is)
+		}
+	}
+}
+
+func TestMustCompile(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustCompile did not panic for invalid path")
+		}
+	}()
+	p := MustCompile("[1][*]")
+	if p.String() != "[1][*]" {
+		t.Errorf("wrong path %v", p)
+	}
+	MustCompile("[")
+}
+
+func ExampleQuery() {
+	header := mustEncode([]interface{}{[]uint{7, 8, 9}, "extra"})
+	values, _ := Query(header, "[0][*]")
+	fmt.Printf("%x\n", values)
+	// Output: [07 08 09]
+}
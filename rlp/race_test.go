@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package rlp
+
+// raceEnabled is set when the race detector is on. It changes how many
+// allocations encoding takes.
+const raceEnabled = true
@@ -1,11 +1,269 @@
 package rlp
 
-import "reflect"
+import (
+	"io"
+	"reflect"
+)
 
+// RawValue represents an encoded RLP value and can be used to delay
+// RLP decoding or to precompute an encoding. Note that the decoder does
+// not verify whether the content of RawValues is valid RLP.
 type RawValue []byte
 
 var rawValueType = reflect.TypeOf(RawValue{})
 
+// ListSize returns the encoded size of an RLP list with the given
+// content size.
+func ListSize(contentSize uint64) uint64 {
+	return uint64(headsize(contentSize)) + contentSize
+}
+
+// IntSize returns the encoded size of the integer x.
+func IntSize(x uint64) int {
+	if x < 0x80 {
+		return 1
+	}
+	return 1 + intsize(x)
+}
+
+// Split returns the content of first RLP value and any
+// bytes after the value as subslices of b.
+func Split(b []byte) (k Kind, content, rest []byte, err error) {
+	k, ts, cs, err := readKind(b)
+	if err != nil {
+		return 0, nil, b, err
+	}
+	return k, b[ts : ts+cs], b[ts+cs:], nil
+}
+
+// SplitString splits b into the content of an RLP string
+// and any remaining bytes after the string.
+func SplitString(b []byte) (content, rest []byte, err error) {
+	k, content, rest, err := Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if k == List {
+		return nil, b, ErrExpectedString
+	}
+	return content, rest, nil
+}
+
+// SplitUint64 decodes an integer at the beginning of b.
+// It also returns the remaining data after the integer in 'rest'.
+func SplitUint64(b []byte) (x uint64, rest []byte, err error) {
+	content, rest, err := SplitString(b)
+	if err != nil {
+		return 0, b, err
+	}
+	switch {
+	case len(content) == 0:
+		return 0, rest, nil
+	case len(content) == 1:
+		if content[0] == 0 {
+			return 0, b, ErrCanonInt
+		}
+		return uint64(content[0]), rest, nil
+	case len(content) > 8:
+		return 0, b, errUintOverflow
+	default:
+		x, err = readSize(content, byte(len(content)))
+		if err != nil {
+			return 0, b, ErrCanonInt
+		}
+		return x, rest, nil
+	}
+}
+
+// SplitList splits b into the content of a list and any remaining
+// bytes after the list.
+func SplitList(b []byte) (content, rest []byte, err error) {
+	k, content, rest, err := Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if k != List {
+		return nil, b, ErrExpectedList
+	}
+	return content, rest, nil
+}
+
+// CountValues counts the number of encoded values in b.
+func CountValues(b []byte) (int, error) {
+	i := 0
+	for ; len(b) > 0; i++ {
+		_, tagsize, size, err := readKind(b)
+		if err != nil {
+			return 0, err
+		}
+		b = b[tagsize+size:]
+	}
+	return i, nil
+}
+
+// Split is like the package-level Split, but also checks that a list value
+// stays within the depth and element limits of l. Use it to take apart
+// untrusted input.
+func (l Limits) Split(b []byte) (k Kind, content, rest []byte, err error) {
+	k, content, rest, err = Split(b)
+	if err != nil {
+		return 0, nil, b, err
+	}
+	if k == List {
+		if err := checkLimits(content, l, 1); err != nil {
+			return 0, nil, b, err
+		}
+	}
+	return k, content, rest, nil
+}
+
+// SplitList is like the package-level SplitList, but also checks that the
+// list stays within the depth and element limits of l.
+func (l Limits) SplitList(b []byte) (content, rest []byte, err error) {
+	k, content, rest, err := l.Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if k != List {
+		return nil, b, ErrExpectedList
+	}
+	return content, rest, nil
+}
+
+// checkLimits verifies that the values in b, which is the content of a list
+// at the given depth, stay within the limits of l.
+func checkLimits(b []byte, l Limits, depth int) error {
+	for n := 0; len(b) > 0; n++ {
+		if l.MaxElems > 0 && n >= l.MaxElems {
+			return ErrTooManyElems
+		}
+		k, ts, cs, err := readKind(b)
+		if err != nil {
+			return err
+		}
+		if k == List {
+			if l.MaxDepth > 0 && depth >= l.MaxDepth {
+				return ErrListTooDeep
+			}
+			if err := checkLimits(b[ts:ts+cs], l, depth+1); err != nil {
+				return err
+			}
+		}
+		b = b[ts+cs:]
+	}
+	return nil
+}
+
+func readKind(buf []byte) (k Kind, tagsize, contentsize uint64, err error) {
+	if len(buf) == 0 {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+	b := buf[0]
+	switch {
+	case b < 0x80:
+		k = Byte
+		tagsize = 0
+		contentsize = 1
+	case b < 0xB8:
+		k = String
+		tagsize = 1
+		contentsize = uint64(b - 0x80)
+		// Reject strings that should've been single bytes.
+		if contentsize == 1 && len(buf) > 1 && buf[1] < 128 {
+			return 0, 0, 0, ErrCanonSize
+		}
+	case b < 0xC0:
+		k = String
+		tagsize = uint64(b-0xB7) + 1
+		contentsize, err = readSize(buf[1:], b-0xB7)
+	case b < 0xF8:
+		k = List
+		tagsize = 1
+		contentsize = uint64(b - 0xC0)
+	default:
+		k = List
+		tagsize = uint64(b-0xF7) + 1
+		contentsize, err = readSize(buf[1:], b-0xF7)
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// Reject values larger than the input slice.
+	if contentsize > uint64(len(buf))-tagsize {
+		return 0, 0, 0, ErrValueTooLarge
+	}
+	return k, tagsize, contentsize, err
+}
+
+func readSize(b []byte, slen byte) (uint64, error) {
+	if int(slen) > len(b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	var s uint64
+	switch slen {
+	case 1:
+		s = uint64(b[0])
+	case 2:
+		s = uint64(b[0])<<8 | uint64(b[1])
+	case 3:
+		s = uint64(b[0])<<16 | uint64(b[1])<<8 | uint64(b[2])
+	case 4:
+		s = uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3])
+	case 5:
+		s = uint64(b[0])<<32 | uint64(b[1])<<24 | uint64(b[2])<<16 | uint64(b[3])<<8 | uint64(b[4])
+	case 6:
+		s = uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+	case 7:
+		s = uint64(b[0])<<48 | uint64(b[1])<<40 | uint64(b[2])<<32 | uint64(b[3])<<24 | uint64(b[4])<<16 | uint64(b[5])<<8 | uint64(b[6])
+	case 8:
+		s = uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 | uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+	}
+	// Reject sizes < 56 (shouldn't have separate size) and sizes with
+	// leading zero bytes.
+	if s < 56 || b[0] == 0 {
+		return 0, ErrCanonSize
+	}
+	return s, nil
+}
+
+// AppendListHeader appends the header of an RLP list with the given
+// content size to b. The list content must be appended by the caller.
+func AppendListHeader(b []byte, contentSize uint64) []byte {
+	return appendHead(b, 0xC0, 0xF7, contentSize)
+}
+
+// AppendStringHeader appends the header of an RLP string of the given
+// size to b. Note that single bytes below 0x80 have no header, use
+// AppendBytes for those.
+func AppendStringHeader(b []byte, size uint64) []byte {
+	return appendHead(b, 0x80, 0xB7, size)
+}
+
+// AppendBytes appends the RLP encoding of s to b.
+func AppendBytes(b []byte, s []byte) []byte {
+	if len(s) == 1 && s[0] <= 0x7f {
+		return append(b, s[0])
+	}
+	b = AppendStringHeader(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// AppendString appends the RLP encoding of s to b.
+func AppendString(b []byte, s string) []byte {
+	if len(s) == 1 && s[0] <= 0x7f {
+		return append(b, s[0])
+	}
+	b = AppendStringHeader(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendHead(b []byte, smalltag, largetag byte, size uint64) []byte {
+	var buf [9]byte
+	n := puthead(buf[:], smalltag, largetag, size)
+	return append(b, buf[:n]...)
+}
+
+// AppendUint64 appends the RLP encoding of i to b, and returns the resulting slice.
 func AppendUint64(b []byte, i uint64) []byte {
 	if i == 0 {
 		return append(b, 0x80)
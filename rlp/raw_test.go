@@ -0,0 +1,398 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func unhex(str string) []byte {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		panic("invalid hex string: " + str)
+	}
+	return b
+}
+
+func TestLimitsSplitList(t *testing.T) {
+	tests := []struct {
+		input  string
+		limits Limits
+		err    error
+	}{
+		{input: "C3010203", limits: Limits{}},
+		{input: "C3010203", limits: Limits{MaxElems: 3}},
+		{input: "C3010203", limits: Limits{MaxElems: 2}, err: ErrTooManyElems},
+		{input: "C3C201C0", limits: Limits{MaxElems: 2}, err: nil},
+		{input: "C4C3010203", limits: Limits{MaxElems: 2}, err: ErrTooManyElems},
+		{input: "C2C1C0", limits: Limits{MaxDepth: 3}},
+		{input: "C2C1C0", limits: Limits{MaxDepth: 2}, err: ErrListTooDeep},
+		{input: "C2C1C0", limits: Limits{MaxDepth: 1}, err: ErrListTooDeep},
+		{input: "C0", limits: Limits{MaxDepth: 1}},
+		{input: "83646F67", limits: Limits{}, err: ErrExpectedList},
+		{input: "C3C301", limits: Limits{}, err: ErrValueTooLarge},
+	}
+	for i, test := range tests {
+		input := unhex(test.input)
+		content, rest, err := test.limits.SplitList(input)
+		if err != test.err {
+			t.Errorf("test %d: error mismatch: got %v, want %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		// Without limits, the result must be the same.
+		wantContent, wantRest, _ := SplitList(input)
+		if !bytes.Equal(content, wantContent) || !bytes.Equal(rest, wantRest) {
+			t.Errorf("test %d: result mismatch: got (%x, %x), want (%x, %x)", i, content, rest, wantContent, wantRest)
+		}
+	}
+}
+
+type rawWithTail struct {
+	Val  RawValue
+	Tail []RawValue `rlp:"tail"`
+}
+
+func TestRawValueInStruct(t *testing.T) {
+	input := unhex("C782FFFF03C20102")
+	var v rawWithTail
+	if err := DecodeBytes(input, &v); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(v.Val, unhex("82FFFF")) {
+		t.Errorf("wrong Val %x", v.Val)
+	}
+	if len(v.Tail) != 2 || !bytes.Equal(v.Tail[0], unhex("03")) || !bytes.Equal(v.Tail[1], unhex("C20102")) {
+		t.Errorf("wrong Tail %x", v.Tail)
+	}
+	enc, err := EncodeToBytes(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, input) {
+		t.Errorf("re-encoding gives %x, want %x", enc, input)
+	}
+}
+
+func TestCountValues(t *testing.T) {
+	tests := []struct {
+		input string
+		count int
+		err   error
+	}{
+		// simple cases
+		{"", 0, nil},
+		{"00", 1, nil},
+		{"80", 1, nil},
+		{"C0", 1, nil},
+		{"010203", 3, nil},
+		{"01C40607080902", 3, nil},
+		{"820101820202840303030304", 4, nil},
+
+		// size errors
+		{"8142", 0, ErrCanonSize},
+		{"01018142", 0, ErrCanonSize},
+		{"0284020202", 0, ErrValueTooLarge},
+		{"01B800", 0, ErrCanonSize},
+		{"01B9", 0, io.ErrUnexpectedEOF},
+	}
+	for i, test := range tests {
+		count, err := CountValues(unhex(test.input))
+		if count != test.count {
+			t.Errorf("test %d: count mismatch, got %d want %d\ninput: %s", i, count, test.count, test.input)
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: err mismatch, got %q want %q\ninput: %s", i, err, test.err, test.input)
+		}
+	}
+}
+
+func TestSplitString(t *testing.T) {
+	for i, test := range []string{
+		"C0",
+		"C100",
+		"C3010203",
+		"C88363617483646F67",
+		"F8384C6F72656D20697073756D20646F6C6F722073697420616D65742C20636F6E7365637465747572206164697069736963696E6720656C6974",
+	} {
+		if _, _, err := SplitString(unhex(test)); !errors.Is(err, ErrExpectedString) {
+			t.Errorf("test %d: error mismatch: have %q, want %q", i, err, ErrExpectedString)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	for i, test := range []string{
+		"80",
+		"00",
+		"01",
+		"8180",
+		"81FF",
+		"820400",
+		"83636174",
+		"83646F67",
+		"B8384C6F72656D20697073756D20646F6C6F722073697420616D65742C20636F6E7365637465747572206164697069736963696E6720656C6974",
+	} {
+		if _, _, err := SplitList(unhex(test)); !errors.Is(err, ErrExpectedList) {
+			t.Errorf("test %d: error mismatch: have %q, want %q", i, err, ErrExpectedList)
+		}
+	}
+}
+
+func TestSplitUint64(t *testing.T) {
+	tests := []struct {
+		input string
+		val   uint64
+		rest  string
+		err   error
+	}{
+		{"01", 1, "", nil},
+		{"7FFF", 0x7F, "FF", nil},
+		{"80FF", 0, "FF", nil},
+		{"81FAFF", 0xFA, "FF", nil},
+		{"82FAFAFF", 0xFAFA, "FF", nil},
+		{"83FAFAFAFF", 0xFAFAFA, "FF", nil},
+		{"84FAFAFAFAFF", 0xFAFAFAFA, "FF", nil},
+		{"85FAFAFAFAFAFF", 0xFAFAFAFAFA, "FF", nil},
+		{"86FAFAFAFAFAFAFF", 0xFAFAFAFAFAFA, "FF", nil},
+		{"87FAFAFAFAFAFAFAFF", 0xFAFAFAFAFAFAFA, "FF", nil},
+		{"88FAFAFAFAFAFAFAFAFF", 0xFAFAFAFAFAFAFAFA, "FF", nil},
+
+		// errors
+		{"", 0, "", io.ErrUnexpectedEOF},
+		{"00", 0, "00", ErrCanonInt},
+		{"81", 0, "81", ErrValueTooLarge},
+		{"8100", 0, "8100", ErrCanonSize},
+		{"8200FF", 0, "8200FF", ErrCanonInt},
+		{"8103FF", 0, "8103FF", ErrCanonSize},
+		{"89FAFAFAFAFAFAFAFAFAFF", 0, "89FAFAFAFAFAFAFAFAFAFF", errUintOverflow},
+		{"C101", 0, "C101", ErrExpectedString},
+	}
+
+	for i, test := range tests {
+		val, rest, err := SplitUint64(unhex(test.input))
+		if val != test.val {
+			t.Errorf("test %d: val mismatch: got %x, want %x (input %q)", i, val, test.val, test.input)
+		}
+		if !bytes.Equal(rest, unhex(test.rest)) {
+			t.Errorf("test %d: rest mismatch: got %x, want %s (input %q)", i, rest, test.rest, test.input)
+		}
+		if err != test.err {
+			t.Errorf("test %d: error mismatch: got %q, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	ff := func(n int) string { return strings.Repeat("FF", n) }
+	tests := []struct {
+		input     string
+		kind      Kind
+		val, rest string
+		err       error
+	}{
+		{input: "00FFFF", kind: Byte, val: "00", rest: "FFFF"},
+		{input: "01FFFF", kind: Byte, val: "01", rest: "FFFF"},
+		{input: "7FFFFF", kind: Byte, val: "7F", rest: "FFFF"},
+		{input: "80FFFF", kind: String, val: "", rest: "FFFF"},
+		{input: "C3010203", kind: List, val: "010203"},
+
+		// errors
+		{input: "", err: io.ErrUnexpectedEOF},
+
+		{input: "8141", err: ErrCanonSize, rest: "8141"},
+		{input: "B800", err: ErrCanonSize, rest: "B800"},
+		{input: "B802FFFF", err: ErrCanonSize, rest: "B802FFFF"},
+		{input: "B90000", err: ErrCanonSize, rest: "B90000"},
+		{input: "B90055", err: ErrCanonSize, rest: "B90055"},
+		{input: "BA0002FFFF", err: ErrCanonSize, rest: "BA0002FFFF"},
+		{input: "F800", err: ErrCanonSize, rest: "F800"},
+		{input: "F90000", err: ErrCanonSize, rest: "F90000"},
+		{input: "F90055", err: ErrCanonSize, rest: "F90055"},
+		{input: "FA0002FFFF", err: ErrCanonSize, rest: "FA0002FFFF"},
+
+		{input: "81", err: ErrValueTooLarge, rest: "81"},
+		{input: "8501010101", err: ErrValueTooLarge, rest: "8501010101"},
+		{input: "C60607080902", err: ErrValueTooLarge, rest: "C60607080902"},
+		{input: "B9", err: io.ErrUnexpectedEOF, rest: "B9"},
+		{input: "F9FF", err: io.ErrUnexpectedEOF, rest: "F9FF"},
+
+		// size check overflow
+		{input: "BFFFFFFFFFFFFFFFFF", err: ErrValueTooLarge, rest: "BFFFFFFFFFFFFFFFFF"},
+		{input: "FFFFFFFFFFFFFFFFFF", err: ErrValueTooLarge, rest: "FFFFFFFFFFFFFFFFFF"},
+		{input: "B838" + ff(55), err: ErrValueTooLarge, rest: "B838" + ff(55)},
+		{input: "F838" + ff(55), err: ErrValueTooLarge, rest: "F838" + ff(55)},
+
+		// a few bigger values, just for kicks
+		{input: "B838" + ff(56) + "01", kind: String, val: ff(56), rest: "01"},
+		{input: "F839" + ff(57), kind: List, val: ff(57), rest: ""},
+		{input: "B90400" + ff(1024), kind: String, val: ff(1024), rest: ""},
+		{input: "F90400" + ff(1024) + "C0", kind: List, val: ff(1024), rest: "C0"},
+	}
+
+	for i, test := range tests {
+		kind, val, rest, err := Split(unhex(test.input))
+		if kind != test.kind {
+			t.Errorf("test %d: kind mismatch: got %v, want %v", i, kind, test.kind)
+		}
+		if !bytes.Equal(val, unhex(test.val)) {
+			t.Errorf("test %d: val mismatch: got %x, want %s", i, val, test.val)
+		}
+		if !bytes.Equal(rest, unhex(test.rest)) {
+			t.Errorf("test %d: rest mismatch: got %x, want %s", i, rest, test.rest)
+		}
+		if err != test.err {
+			t.Errorf("test %d: error mismatch: got %q, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestReadSize(t *testing.T) {
+	tests := []struct {
+		input string
+		slen  byte
+		size  uint64
+		err   error
+	}{
+		{input: "", slen: 1, err: io.ErrUnexpectedEOF},
+		{input: "FF", slen: 2, err: io.ErrUnexpectedEOF},
+		{input: "00", slen: 1, err: ErrCanonSize},
+		{input: "36", slen: 1, err: ErrCanonSize},
+		{input: "37", slen: 1, err: ErrCanonSize},
+		{input: "38", slen: 1, size: 0x38},
+		{input: "FF", slen: 1, size: 0xFF},
+		{input: "FFFF", slen: 2, size: 0xFFFF},
+		{input: "FFFFFF", slen: 3, size: 0xFFFFFF},
+		{input: "FFFFFFFF", slen: 4, size: 0xFFFFFFFF},
+		{input: "FFFFFFFFFF", slen: 5, size: 0xFFFFFFFFFF},
+		{input: "FFFFFFFFFFFF", slen: 6, size: 0xFFFFFFFFFFFF},
+		{input: "FFFFFFFFFFFFFF", slen: 7, size: 0xFFFFFFFFFFFFFF},
+		{input: "FFFFFFFFFFFFFFFF", slen: 8, size: 0xFFFFFFFFFFFFFFFF},
+		{input: "0102", slen: 2, size: 0x0102},
+		{input: "0001", slen: 2, err: ErrCanonSize},
+		{input: "0100", slen: 2, size: 0x0100},
+		{input: "010203", slen: 3, size: 0x010203},
+		{input: "00010203", slen: 4, err: ErrCanonSize},
+	}
+
+	for _, test := range tests {
+		size, err := readSize(unhex(test.input), test.slen)
+		if err != test.err {
+			t.Errorf("readSize(%s, %d): error mismatch: got %q, want %q", test.input, test.slen, err, test.err)
+			continue
+		}
+		if size != test.size {
+			t.Errorf("readSize(%s, %d): size mismatch: got %#x, want %#x", test.input, test.slen, size, test.size)
+		}
+	}
+}
+
+func TestAppendUint64(t *testing.T) {
+	tests := []struct {
+		input  uint64
+		slice  []byte
+		output string
+	}{
+		{0, nil, "80"},
+		{1, nil, "01"},
+		{2, nil, "02"},
+		{127, nil, "7F"},
+		{128, nil, "8180"},
+		{129, nil, "8181"},
+		{0xFFFFFF, nil, "83FFFFFF"},
+		{127, []byte{1, 2, 3}, "0102037F"},
+		{0xFFFFFF, []byte{1, 2, 3}, "01020383FFFFFF"},
+	}
+
+	for _, test := range tests {
+		x := AppendUint64(test.slice, test.input)
+		if !bytes.Equal(x, unhex(test.output)) {
+			t.Errorf("AppendUint64(%v, %d): got %x, want %s", test.slice, test.input, x, test.output)
+		}
+
+		// Check that IntSize returns the appended size.
+		length := len(x) - len(test.slice)
+		if s := IntSize(test.input); s != length {
+			t.Errorf("IntSize(%d): got %d, want %d", test.input, s, length)
+		}
+	}
+}
+
+func TestAppendUint64Random(t *testing.T) {
+	fn := func(i uint64) bool {
+		enc, _ := EncodeToBytes(i)
+		encAppend := AppendUint64(nil, i)
+		return bytes.Equal(enc, encAppend) && IntSize(i) == len(enc)
+	}
+	config := quick.Config{MaxCountScale: 50}
+	if err := quick.Check(fn, &config); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendBytesRandom(t *testing.T) {
+	fn := func(b []byte) bool {
+		enc, _ := EncodeToBytes(b)
+		return bytes.Equal(AppendBytes(nil, b), enc) && bytes.Equal(AppendString(nil, string(b)), enc)
+	}
+	if err := quick.Check(fn, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendHeaders(t *testing.T) {
+	tests := []struct {
+		size         uint64
+		list, string string
+	}{
+		{0, "C0", "80"},
+		{1, "C1", "81"},
+		{55, "F7", "B7"},
+		{56, "F838", "B838"},
+		{0xFF, "F8FF", "B8FF"},
+		{0x100, "F90100", "B90100"},
+		{0xFFFFFF, "FAFFFFFF", "BAFFFFFF"},
+		{0xFFFFFFFFFFFFFFFF, "FFFFFFFFFFFFFFFFFF", "BFFFFFFFFFFFFFFFFF"},
+	}
+	prefix := []byte{1, 2}
+	for _, test := range tests {
+		if h := AppendListHeader(prefix, test.size); !bytes.Equal(h, unhex("0102"+test.list)) {
+			t.Errorf("AppendListHeader(%d): got %x, want 0102%s", test.size, h, test.list)
+		}
+		if h := AppendStringHeader(prefix, test.size); !bytes.Equal(h, unhex("0102"+test.string)) {
+			t.Errorf("AppendStringHeader(%d): got %x, want 0102%s", test.size, h, test.string)
+		}
+		if s := ListSize(test.size); s != uint64(len(unhex(test.list)))+test.size {
+			t.Errorf("ListSize(%d): got %d", test.size, s)
+		}
+	}
+}
+
+func TestAppendBytes(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"", "80"},
+		{"00", "00"},
+		{"7F", "7F"},
+		{"80", "8180"},
+		{"0102", "820102"},
+		{strings.Repeat("61", 56), "B838" + strings.Repeat("61", 56)},
+	}
+	for _, test := range tests {
+		in := unhex(test.input)
+		if out := AppendBytes([]byte{0xFF}, in); !bytes.Equal(out, unhex("FF"+test.output)) {
+			t.Errorf("AppendBytes(%s): got %x, want FF%s", test.input, out, test.output)
+		}
+		if out := AppendString([]byte{0xFF}, string(in)); !bytes.Equal(out, unhex("FF"+test.output)) {
+			t.Errorf("AppendString(%s): got %x, want FF%s", test.input, out, test.output)
+		}
+	}
+}
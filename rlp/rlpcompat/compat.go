@@ -0,0 +1,444 @@
+// Package rlpcompat checks that two versions of a struct type have compatible
+// RLP encodings. It reports changes which break decoding of data written by
+// the other version: removed and reordered fields, fields added without the
+// "optional" tag, required fields following optional ones and changes of the
+// encoding of field types.
+package rlpcompat
+
+import (
+	"awesomeProject/rlp/internal/rlpstruct"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"reflect"
+)
+
+const pathOfPackageRLP = "awesomeProject/rlp"
+
+// Version is one version of the checked package.
+type Version struct {
+	pkg          *types.Package
+	encoderIface *types.Interface
+	rawValueType types.Type
+}
+
+// Load type-checks the package in dir. Packages are loaded with the build tags
+// of build.Default. Add the norlpgen tag to exclude generated encoders, which
+// follow the struct layout and would hide it otherwise.
+func Load(dir string) (*Version, error) {
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+	pkg, err := imp.ImportFrom(".", dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	packageRLP := pkg
+	if pkg.Path() != pathOfPackageRLP {
+		packageRLP, err = imp.ImportFrom(pathOfPackageRLP, dir, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewVersion(pkg, packageRLP), nil
+}
+
+// NewVersion creates a Version of the type-checked package pkg. packageRLP is
+// package rlp as imported by pkg.
+func NewVersion(pkg, packageRLP *types.Package) *Version {
+	enc := packageRLP.Scope().Lookup("Encoder").Type().Underlying()
+	return &Version{
+		pkg:          pkg,
+		encoderIface: enc.(*types.Interface),
+		rawValueType: packageRLP.Scope().Lookup("RawValue").Type(),
+	}
+}
+
+// wireKind classifies types by their encoding. A value of one type can be
+// decoded into another type of the same kind, within the size limits of the
+// target type.
+type wireKind int
+
+const (
+	wireInteger wireKind = iota // unsigned integers, bool, big.Int and u256.Int
+	wireString                  // strings, byte slices and byte arrays
+	wireList                    // slices and arrays of other types
+	wireStruct                  // structs
+	wireOpaque                  // RawValue, interfaces and types with custom encoders
+)
+
+var wireKindNames = [...]string{
+	wireInteger: "integer",
+	wireString:  "string",
+	wireList:    "list",
+	wireStruct:  "struct",
+	wireOpaque:  "custom",
+}
+
+func (k wireKind) String() string {
+	return wireKindNames[k]
+}
+
+// wireType describes the encoding of a type.
+type wireType struct {
+	typ     types.Type
+	kind    wireKind
+	bits    int               // size of integers, 0 if unbounded
+	length  int               // length of byte arrays, -1 for variable length
+	nilKind rlpstruct.NilKind // encoding of nil for pointers, zero otherwise
+	elem    *wireType         // list element
+}
+
+func (v *Version) wireType(typ types.Type, tags rlpstruct.Tags) *wireType {
+	w := &wireType{typ: typ, length: -1}
+	switch {
+	case types.Identical(typ, v.rawValueType):
+		w.kind = wireOpaque
+		return w
+	case isNamed(typ, "math/big", "Int"):
+		w.kind = wireInteger
+		return w
+	case isNamed(typ, "awesomeProject/common/u256", "Int"):
+		w.kind, w.bits = wireInteger, 256
+		return w
+	}
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		elem := v.wireType(ptr.Elem(), rlpstruct.Tags{})
+		elem.typ = typ
+		if tags.NilOK {
+			elem.nilKind = tags.NilKind
+		} else {
+			elem.nilKind = v.structType(ptr.Elem()).DeaultNilValue()
+		}
+		return elem
+	}
+	if v.isEncoder(types.NewPointer(typ)) {
+		w.kind = wireOpaque
+		return w
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		w.kind = wireInteger
+		switch {
+		case u.Kind() == types.Bool:
+			w.bits = 1
+		case u.Kind() == types.String:
+			w.kind = wireString
+		case u.Kind() == types.Uint8:
+			w.bits = 8
+		case u.Kind() == types.Uint16:
+			w.bits = 16
+		case u.Kind() == types.Uint32:
+			w.bits = 32
+		case u.Info()&types.IsUnsigned != 0:
+			w.bits = 64
+		default:
+			w.kind = wireOpaque
+		}
+	case *types.Slice:
+		if v.isByte(u.Elem()) {
+			w.kind = wireString
+		} else {
+			w.kind = wireList
+			w.elem = v.wireType(u.Elem(), rlpstruct.Tags{})
+		}
+	case *types.Array:
+		if v.isByte(u.Elem()) {
+			w.kind = wireString
+			w.length = int(u.Len())
+		} else {
+			w.kind = wireList
+			w.elem = v.wireType(u.Elem(), rlpstruct.Tags{})
+		}
+	case *types.Struct:
+		w.kind = wireStruct
+	default:
+		w.kind = wireOpaque
+	}
+	return w
+}
+
+func (w *wireType) String() string {
+	switch {
+	case w.kind == wireInteger && w.bits > 0:
+		return fmt.Sprintf("%v (%d-bit integer)", w.typ, w.bits)
+	case w.kind == wireString && w.length >= 0:
+		return fmt.Sprintf("%v (string of length %d)", w.typ, w.length)
+	default:
+		return fmt.Sprintf("%v (%v)", w.typ, w.kind)
+	}
+}
+
+func (v *Version) isEncoder(typ types.Type) bool {
+	return types.Implements(typ, v.encoderIface)
+}
+
+func (v *Version) isByte(typ types.Type) bool {
+	b, ok := typ.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Uint8 && !v.isEncoder(typ)
+}
+
+func isNamed(typ types.Type, pkg, name string) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// structType converts typ to rlpstruct.Type.
+func (v *Version) structType(typ types.Type) *rlpstruct.Type {
+	t := &rlpstruct.Type{
+		Name:      types.TypeString(typ, nil),
+		Kind:      typeReflectKind(typ),
+		IsEncoder: v.isEncoder(typ),
+	}
+	switch u := typ.Underlying().(type) {
+	case *types.Array:
+		t.Elem = v.structType(u.Elem())
+	case *types.Slice:
+		t.Elem = v.structType(u.Elem())
+	case *types.Pointer:
+		// Only the kind of the pointer element matters to rlpstruct.
+		t.Elem = &rlpstruct.Type{Kind: typeReflectKind(u.Elem())}
+	}
+	return t
+}
+
+// typeReflectKind gives the reflect.Kind that represents typ.
+func typeReflectKind(typ types.Type) reflect.Kind {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		k := typ.Kind()
+		if k >= types.Bool && k <= types.Complex128 {
+			// value order matches for Bool..Complex128
+			return reflect.Bool + reflect.Kind(k-types.Bool)
+		}
+		if k == types.String {
+			return reflect.String
+		}
+		return reflect.UnsafePointer
+	case *types.Array:
+		return reflect.Array
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Map:
+		return reflect.Map
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Signature:
+		return reflect.Func
+	case *types.Slice:
+		return reflect.Slice
+	default:
+		return reflect.Struct
+	}
+}
+
+// structField is an encoded struct field.
+type structField struct {
+	name     string
+	position int
+	typ      types.Type
+	tags     rlpstruct.Tags
+}
+
+// structFields resolves the encoded fields of typ. Unlike the encoder, it
+// accepts required fields after optional ones, they are reported by the
+// checker. Fields of inlined structs are matched by their own name, so
+// moving fields into an inlined struct is not a change.
+func (v *Version) structFields(typ *types.Struct) ([]*structField, error) {
+	fields, tags, err := rlpstruct.ParseFields(v.structFieldList(typ))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*structField, len(fields))
+	for i, f := range fields {
+		result[i] = &structField{
+			name:     f.Name,
+			position: i,
+			typ:      fieldType(typ, f.Path),
+			tags:     tags[i],
+		}
+	}
+	return result, nil
+}
+
+// structFieldList converts the fields of typ, including the fields of
+// embedded structs.
+func (v *Version) structFieldList(typ *types.Struct) []rlpstruct.Field {
+	var allFields []rlpstruct.Field
+	for i := 0; i < typ.NumFields(); i++ {
+		f := typ.Field(i)
+		sf := rlpstruct.Field{
+			Name:     f.Name(),
+			Exported: f.Exported(),
+			Embedded: f.Embedded(),
+			Index:    i,
+			Tag:      typ.Tag(i),
+			Type:     *v.structType(f.Type()),
+		}
+		if st, ok := f.Type().Underlying().(*types.Struct); ok && f.Embedded() {
+			sf.Fields = v.structFieldList(st)
+		}
+		allFields = append(allFields, sf)
+	}
+	return allFields
+}
+
+// fieldType returns the type of the field with the given index sequence.
+func fieldType(typ *types.Struct, path []int) types.Type {
+	var ft types.Type
+	for _, index := range path {
+		ft = typ.Field(index).Type()
+		if st, ok := ft.Underlying().(*types.Struct); ok {
+			typ = st
+		}
+	}
+	return ft
+}
+
+// checker compares the old and new version of a type.
+type checker struct {
+	old, new *Version
+	issues   []string
+	visited  map[[2]string]bool // pairs of struct types compared already
+}
+
+// Check compares the struct type with the given name in both versions. It
+// returns the breaking changes found, one line each, starting with the path
+// of the affected field.
+func Check(old, new *Version, name string) ([]string, error) {
+	oldType, err := lookupStruct(old.pkg, name)
+	if err != nil {
+		return nil, fmt.Errorf("old version: %v", err)
+	}
+	newType, err := lookupStruct(new.pkg, name)
+	if err != nil {
+		return nil, fmt.Errorf("new version: %v", err)
+	}
+	c := &checker{old: old, new: new, visited: make(map[[2]string]bool)}
+	c.compareStruct(name, oldType, newType)
+	return c.issues, nil
+}
+
+func lookupStruct(pkg *types.Package, name string) (types.Type, error) {
+	obj := pkg.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("no type %s in %s", name, pkg.Path())
+	}
+	if _, ok := obj.(*types.TypeName); !ok {
+		return nil, fmt.Errorf("%s.%s is not a type", pkg.Path(), name)
+	}
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("%s.%s is not a struct type", pkg.Path(), name)
+	}
+	return obj.Type(), nil
+}
+
+func (c *checker) report(path, format string, args ...interface{}) {
+	c.issues = append(c.issues, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *checker) compareStruct(path string, oldType, newType types.Type) {
+	key := [2]string{oldType.String(), newType.String()}
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	oldFields, err := c.old.structFields(oldType.Underlying().(*types.Struct))
+	if err != nil {
+		c.report(path, "old version: %v", err)
+		return
+	}
+	newFields, err := c.new.structFields(newType.Underlying().(*types.Struct))
+	if err != nil {
+		c.report(path, "%v", err)
+		return
+	}
+
+	// Check the new field order.
+	firstOptional := ""
+	for _, f := range newFields {
+		switch {
+		case f.tags.Optional || f.tags.Tail:
+			if firstOptional == "" {
+				firstOptional = f.name
+			}
+		case firstOptional != "":
+			c.report(path+"."+f.name, "required field follows optional field %s", firstOptional)
+		}
+	}
+
+	// Compare fields present in both versions.
+	newByName := make(map[string]*structField, len(newFields))
+	for _, f := range newFields {
+		newByName[f.name] = f
+	}
+	oldByName := make(map[string]*structField, len(oldFields))
+	for _, of := range oldFields {
+		oldByName[of.name] = of
+		fieldPath := path + "." + of.name
+		nf := newByName[of.name]
+		if nf == nil {
+			c.report(fieldPath, "field removed")
+			continue
+		}
+		if nf.position != of.position {
+			c.report(fieldPath, "field moved from position %d to %d", of.position, nf.position)
+		}
+		if of.tags.Optional && !nf.tags.Optional && !nf.tags.Tail {
+			c.report(fieldPath, "optional field became required")
+		}
+		if of.tags.Tail != nf.tags.Tail {
+			c.report(fieldPath, "tail tag changed")
+		}
+		c.compare(fieldPath, c.old.wireType(of.typ, of.tags), c.new.wireType(nf.typ, nf.tags))
+	}
+
+	// Added fields must be optional.
+	for _, nf := range newFields {
+		if oldByName[nf.name] == nil && !nf.tags.Optional && !nf.tags.Tail {
+			c.report(path+"."+nf.name, "field added without optional tag")
+		}
+	}
+}
+
+func (c *checker) compare(path string, old, new *wireType) {
+	if old.kind != new.kind {
+		c.report(path, "type changed from %v to %v", old, new)
+		return
+	}
+	if old.nilKind != 0 && new.nilKind != 0 && old.nilKind != new.nilKind {
+		c.report(path, "encoding of nil changed from %#x to %#x", byte(old.nilKind), byte(new.nilKind))
+	}
+	switch old.kind {
+	case wireInteger:
+		if new.bits != 0 && (old.bits == 0 || new.bits < old.bits) {
+			c.report(path, "integer narrowed from %v to %v", old, new)
+		}
+	case wireString:
+		if new.length >= 0 && new.length != old.length {
+			c.report(path, "type changed from %v to %v", old, new)
+		}
+	case wireList:
+		c.compare(path+"[]", old.elem, new.elem)
+	case wireStruct:
+		c.compareStruct(path, derefType(old.typ), derefType(new.typ))
+	case wireOpaque:
+		if old.typ.String() != new.typ.String() {
+			c.report(path, "custom encoding can't be compared, type changed from %v to %v", old.typ, new.typ)
+		}
+	}
+}
+
+func derefType(typ types.Type) types.Type {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,214 @@
+package rlpcompat
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+// testImporter is shared by all tests, importing package rlp from source
+// takes a while.
+var testImporter = importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+
+// loadSource type-checks a package with the given declarations.
+func loadSource(t *testing.T, src string) *Version {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", "package test\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: testImporter}
+	pkg, err := conf.Check("test", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packageRLP, err := testImporter.ImportFrom(pathOfPackageRLP, ".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewVersion(pkg, packageRLP)
+}
+
+var checkTests = []struct {
+	name     string
+	old, new string
+	issues   []string
+}{
+	{
+		name: "unchanged",
+		old:  `type T struct { A uint64; B []byte }`,
+		new:  `type T struct { A uint64; B []byte }`,
+	},
+	{
+		name: "optional field added",
+		old:  `type T struct { A uint64 }`,
+		new:  "type T struct { A uint64; B uint64 `rlp:\"optional\"` }",
+	},
+	{
+		name:   "required field added",
+		old:    `type T struct { A uint64 }`,
+		new:    `type T struct { A uint64; B uint64 }`,
+		issues: []string{"T.B: field added without optional tag"},
+	},
+	{
+		name:   "field removed",
+		old:    `type T struct { A, B uint64 }`,
+		new:    `type T struct { A uint64 }`,
+		issues: []string{"T.B: field removed"},
+	},
+	{
+		name: "fields swapped",
+		old:  `type T struct { A, B uint64 }`,
+		new:  `type T struct { B, A uint64 }`,
+		issues: []string{
+			"T.A: field moved from position 0 to 1",
+			"T.B: field moved from position 1 to 0",
+		},
+	},
+	{
+		name: "fields ignored and unexported",
+		old:  `type T struct { A uint64 }`,
+		new:  "type T struct { A uint64; b uint64; C uint64 `rlp:\"-\"` }",
+	},
+	{
+		name: "required after optional",
+		old:  "type T struct { A uint64 `rlp:\"optional\"` }",
+		new:  "type T struct { A uint64 `rlp:\"optional\"`; B uint64 }",
+		issues: []string{
+			"T.B: required field follows optional field A",
+			"T.B: field added without optional tag",
+		},
+	},
+	{
+		name:   "optional became required",
+		old:    "type T struct { A uint64 `rlp:\"optional\"` }",
+		new:    `type T struct { A uint64 }`,
+		issues: []string{"T.A: optional field became required"},
+	},
+	{
+		name: "integer widened",
+		old:  `type T struct { A uint16; B uint64 }`,
+		new:  `type T struct { A uint32; B *big.Int }`,
+	},
+	{
+		name: "integer narrowed",
+		old:  `type T struct { A uint64; B *big.Int }`,
+		new:  `type T struct { A uint32; B uint64 }`,
+		issues: []string{
+			"T.A: integer narrowed from uint64 (64-bit integer) to uint32 (32-bit integer)",
+			"T.B: integer narrowed from *math/big.Int (integer) to uint64 (64-bit integer)",
+		},
+	},
+	{
+		name: "string types",
+		old:  `type T struct { A string; B [20]byte; C [20]byte }`,
+		new:  `type T struct { A []byte; B []byte; C [32]byte }`,
+		issues: []string{
+			"T.C: type changed from [20]byte (string of length 20) to [32]byte (string of length 32)",
+		},
+	},
+	{
+		name:   "string became list",
+		old:    `type T struct { A []byte }`,
+		new:    `type T struct { A []uint16 }`,
+		issues: []string{"T.A: type changed from []byte (string) to []uint16 (list)"},
+	},
+	{
+		name:   "list element",
+		old:    `type T struct { A []uint64 }`,
+		new:    `type T struct { A []uint16 }`,
+		issues: []string{"T.A[]: integer narrowed from uint64 (64-bit integer) to uint16 (16-bit integer)"},
+	},
+	{
+		name:   "nested struct",
+		old:    `type T struct { A []*U }; type U struct { X, Y uint }`,
+		new:    `type T struct { A []*U }; type U struct { X uint }`,
+		issues: []string{"T.A[].Y: field removed"},
+	},
+	{
+		name:   "nil encoding",
+		old:    "type T struct { A *uint64 `rlp:\"nilString\"` }",
+		new:    "type T struct { A *uint64 `rlp:\"nilList\"` }",
+		issues: []string{"T.A: encoding of nil changed from 0x80 to 0xc0"},
+	},
+	{
+		name: "moved into inlined struct",
+		old:  `type T struct { A, B, C uint }`,
+		new:  "type T struct { A uint; Inner `rlp:\"inline\"`; C uint }; type Inner struct { B uint }",
+	},
+	{
+		name: "recursive struct",
+		old:  "type T struct { A uint; Next *T `rlp:\"nil\"` }",
+		new:  "type T struct { A uint; Next *T `rlp:\"nil\"`; B uint `rlp:\"optional\"` }",
+	},
+	{
+		name:   "custom encoder",
+		old:    `type T struct { A rlp.RawValue }`,
+		new:    `type T struct { A E }; type E struct{}; func (E) EncodeRLP(io.Writer) error { return nil }`,
+		issues: []string{"T.A: custom encoding can't be compared, type changed from awesomeProject/rlp.RawValue to test.E"},
+	},
+	{
+		name:   "invalid tags",
+		old:    `type T struct { A uint }`,
+		new:    "type T struct { A uint `rlp:\"tail\"` }",
+		issues: []string{"T: rlp: invalid struct tag \"tail\" for field A (field type is not slice)"},
+	},
+}
+
+const testImports = `
+import (
+	"awesomeProject/rlp"
+	"io"
+	"math/big"
+)
+
+var (
+	_ = rlp.RawValue{}
+	_ io.Writer
+	_ big.Int
+)
+`
+
+func TestCheck(t *testing.T) {
+	for _, test := range checkTests {
+		t.Run(test.name, func(t *testing.T) {
+			old := loadSource(t, testImports+test.old)
+			new := loadSource(t, testImports+test.new)
+			issues, err := Check(old, new, "T")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("wrong issues\ngot:  %q\nwant: %q", issues, test.issues)
+			}
+		})
+	}
+}
+
+func TestCheckLookupErrors(t *testing.T) {
+	v := loadSource(t, `type T struct{}; type S []uint; var V T`)
+	for _, name := range []string{"X", "S", "V"} {
+		if _, err := Check(v, v, name); err == nil {
+			t.Errorf("no error for %s", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	v, err := Load("../../core/types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := Check(v, v, "Header")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) > 0 {
+		t.Fatalf("issues for identical versions: %q", issues)
+	}
+}
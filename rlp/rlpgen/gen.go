@@ -0,0 +1,942 @@
+package main
+
+import (
+	"awesomeProject/rlp/internal/rlpstruct"
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// buildContext keeps the data needed for make*Op.
+type buildContext struct {
+	topType *types.Named // the type we're creating methods for
+
+	encoderIface *types.Interface
+	decoderIface *types.Interface
+	rawValueType *types.Named
+
+	// hasEncoder and hasDecoder say which methods will exist on topType
+	// once the generated code is compiled.
+	hasEncoder bool
+	hasDecoder bool
+
+	building          map[*types.Named]bool // named struct types being built, for recursion
+	typeToStructCache map[types.Type]*rlpstruct.Type
+}
+
+func newBuildContext(packageRLP *types.Package) *buildContext {
+	enc := packageRLP.Scope().Lookup("Encoder").Type().Underlying()
+	dec := packageRLP.Scope().Lookup("Decoder").Type().Underlying()
+	rawv := packageRLP.Scope().Lookup("RawValue").Type()
+	return &buildContext{
+		encoderIface:      enc.(*types.Interface),
+		decoderIface:      dec.(*types.Interface),
+		rawValueType:      rawv.(*types.Named),
+		building:          make(map[*types.Named]bool),
+		typeToStructCache: make(map[types.Type]*rlpstruct.Type),
+	}
+}
+
+func (bctx *buildContext) isEncoder(typ types.Type) bool {
+	return types.Implements(typ, bctx.encoderIface)
+}
+
+func (bctx *buildContext) isDecoder(typ types.Type) bool {
+	return types.Implements(typ, bctx.decoderIface)
+}
+
+// typeToStructType converts typ to rlpstruct.Type.
+func (bctx *buildContext) typeToStructType(typ types.Type) *rlpstruct.Type {
+	if prev := bctx.typeToStructCache[typ]; prev != nil {
+		return prev // short-circuit for recursive types.
+	}
+	t := &rlpstruct.Type{
+		Name:      types.TypeString(typ, nil),
+		Kind:      typeReflectKind(typ),
+		IsEncoder: bctx.isEncoder(typ),
+		IsDecoder: bctx.isDecoder(typ),
+	}
+	bctx.typeToStructCache[typ] = t
+
+	// Assign element type.
+	switch u := typ.Underlying().(type) {
+	case *types.Array:
+		t.Elem = bctx.typeToStructType(u.Elem())
+	case *types.Slice:
+		t.Elem = bctx.typeToStructType(u.Elem())
+	case *types.Pointer:
+		t.Elem = bctx.typeToStructType(u.Elem())
+	}
+	return t
+}
+
+// structFields resolves the RLP fields of a struct type using the same
+// rules as the reflection-based encoder. The names are field selectors,
+// which include the embedded struct for fields of inlined structs.
+func (bctx *buildContext) structFields(typ *types.Struct) ([]string, []*types.Var, []rlpstruct.Tags, error) {
+	fields, tags, err := rlpstruct.ProcessFields(bctx.structFieldList(typ))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	names := make([]string, len(fields))
+	vars := make([]*types.Var, len(fields))
+	for i, f := range fields {
+		names[i], vars[i] = fieldByPath(typ, f.Path)
+	}
+	return names, vars, tags, nil
+}
+
+// structFieldList converts the fields of typ to rlpstruct.Field. The fields
+// of embedded structs are included for the rlp:"inline" tag.
+func (bctx *buildContext) structFieldList(typ *types.Struct) []rlpstruct.Field {
+	var allFields []rlpstruct.Field
+	for i := 0; i < typ.NumFields(); i++ {
+		f := typ.Field(i)
+		sf := rlpstruct.Field{
+			Name:     f.Name(),
+			Exported: f.Exported(),
+			Embedded: f.Embedded(),
+			Index:    i,
+			Tag:      typ.Tag(i),
+			Type:     *bctx.typeToStructType(f.Type()),
+		}
+		if st, ok := f.Type().Underlying().(*types.Struct); ok && f.Embedded() {
+			sf.Fields = bctx.structFieldList(st)
+		}
+		allFields = append(allFields, sf)
+	}
+	return allFields
+}
+
+// fieldByPath returns the selector and the variable of the field with the
+// given index sequence.
+func fieldByPath(typ *types.Struct, path []int) (string, *types.Var) {
+	var names []string
+	var v *types.Var
+	for _, index := range path {
+		v = typ.Field(index)
+		names = append(names, v.Name())
+		if st, ok := v.Type().Underlying().(*types.Struct); ok {
+			typ = st
+		}
+	}
+	return strings.Join(names, "."), v
+}
+
+// genContext is passed to the gen* methods of op when generating
+// the output code. It tracks packages to be imported by the output
+// file and assigns unique names of temporary variables.
+type genContext struct {
+	inPackage   *types.Package
+	imports     map[string]struct{}
+	tempCounter int
+	path        []pathElem // location of the value being written or read
+}
+
+func newGenContext(inPackage *types.Package) *genContext {
+	return &genContext{
+		inPackage: inPackage,
+		imports:   make(map[string]struct{}),
+	}
+}
+
+func (ctx *genContext) temp() string {
+	v := fmt.Sprintf("_tmp%d", ctx.tempCounter)
+	ctx.tempCounter++
+	return v
+}
+
+func (ctx *genContext) resetTemp() {
+	ctx.tempCounter = 0
+}
+
+func (ctx *genContext) addImport(path string) {
+	if path == ctx.inPackage.Path() {
+		return // avoid importing the package that we're generating in.
+	}
+	ctx.imports[path] = struct{}{}
+}
+
+// importsList returns all packages that need to be imported.
+func (ctx *genContext) importsList() []string {
+	imp := make([]string, 0, len(ctx.imports))
+	for k := range ctx.imports {
+		imp = append(imp, k)
+	}
+	sort.Strings(imp)
+	return imp
+}
+
+// qualify is the types.Qualifier used for printing types.
+func (ctx *genContext) qualify(pkg *types.Package) string {
+	if pkg.Path() == ctx.inPackage.Path() {
+		return ""
+	}
+	ctx.addImport(pkg.Path())
+	return pkg.Name()
+}
+
+// typeString prints typ and records its package for import.
+func (ctx *genContext) typeString(typ types.Type) string {
+	return types.TypeString(typ, ctx.qualify)
+}
+
+// pathElem is an element of the path of the value being processed. It is
+// either a string literal or an expression yielding an index.
+type pathElem struct {
+	lit   string
+	index string
+}
+
+// pushField and pushIndex extend the path of the value being processed by
+// a struct field and a list index.
+func (ctx *genContext) pushField(name string) {
+	ctx.path = append(ctx.path, pathElem{lit: "." + name})
+}
+
+func (ctx *genContext) pushIndex(index string) {
+	ctx.path = append(ctx.path, pathElem{lit: "["}, pathElem{index: index}, pathElem{lit: "]"})
+}
+
+// popPath removes the n most recent path elements.
+func (ctx *genContext) popPath(n int) {
+	ctx.path = ctx.path[:len(ctx.path)-n]
+}
+
+// pathExpr returns a string expression for the current path. Adjacent
+// literals are merged.
+func (ctx *genContext) pathExpr() string {
+	var (
+		parts []string
+		lit   string
+	)
+	for _, p := range ctx.path {
+		if p.index == "" {
+			lit += p.lit
+			continue
+		}
+		if lit != "" {
+			parts = append(parts, strconv.Quote(lit))
+			lit = ""
+		}
+		ctx.addImport("strconv")
+		parts = append(parts, "strconv.Itoa("+p.index+")")
+	}
+	if lit != "" {
+		parts = append(parts, strconv.Quote(lit))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// encodeError returns an expression which annotates err with the path of
+// the value being written.
+func (ctx *genContext) encodeError(err string) string {
+	if len(ctx.path) == 0 {
+		return err
+	}
+	return fmt.Sprintf("%s(%s, %s)", ctx.rlp("WrapEncodeError"), err, ctx.pathExpr())
+}
+
+// decodeError returns an expression which annotates err with the path of
+// the value being read.
+func (ctx *genContext) decodeError(err string) string {
+	if len(ctx.path) == 0 {
+		return err
+	}
+	return fmt.Sprintf("%s(%s, %s)", ctx.rlp("WrapDecodeError"), err, ctx.pathExpr())
+}
+
+// rlp returns a reference to the named symbol of package rlp.
+func (ctx *genContext) rlp(sym string) string {
+	if ctx.inPackage.Path() == pathOfPackageRLP {
+		return sym
+	}
+	ctx.addImport(pathOfPackageRLP)
+	return "rlp." + sym
+}
+
+// nonZero returns an expression that is true when v is not the zero value.
+func (ctx *genContext) nonZero(v string, typ types.Type) string {
+	check := nonZeroCheck(v, typ, ctx.qualify)
+	if strings.HasPrefix(check, "!reflect.") {
+		ctx.addImport("reflect")
+	}
+	return check
+}
+
+var tempVarPattern = regexp.MustCompile(`^_tmp[0-9]+$`)
+
+// addressOf returns an expression for the address of a decoded value. Results
+// that are already held in a temporary are referenced directly; anything else
+// is copied into a new temporary first.
+func (ctx *genContext) addressOf(b *bytes.Buffer, result string) string {
+	if tempVarPattern.MatchString(result) {
+		return "&" + result
+	}
+	v := ctx.temp()
+	fmt.Fprintf(b, "%s := %s\n", v, result)
+	return "&" + v
+}
+
+type op interface {
+	// genWrite creates the encoder. The generated code should write v,
+	// which is any Go expression, to the rlp.EncoderBuffer 'w'.
+	genWrite(ctx *genContext, v string) string
+
+	// genDecode creates the decoder. The generated code should read
+	// a value from the rlp.Stream 'dec'. It returns the code and a Go
+	// expression holding the decoded value.
+	genDecode(ctx *genContext) (string, string)
+}
+
+// basicOp handles basic types bool, uint*, string.
+type basicOp struct {
+	typ           types.Type
+	writeMethod   string     // EncoderBuffer writer method name
+	writeArgType  types.Type // parameter type of writeMethod
+	decMethod     string
+	decResultType types.Type // return type of decMethod
+}
+
+func (bctx *buildContext) makeBasicOp(typ *types.Basic) (op, error) {
+	op := basicOp{typ: typ}
+	kind := typ.Kind()
+	switch {
+	case kind == types.Bool:
+		op.writeMethod, op.writeArgType = "WriteBool", typ
+		op.decMethod, op.decResultType = "Bool", typ
+	case kind >= types.Uint8 && kind <= types.Uint64:
+		op.writeMethod, op.writeArgType = "WriteUint64", types.Typ[types.Uint64]
+		op.decMethod, op.decResultType = strings.Title(typ.Name()), typ
+	case kind == types.Uint || kind == types.Uintptr:
+		op.writeMethod, op.writeArgType = "WriteUint64", types.Typ[types.Uint64]
+		op.decMethod, op.decResultType = "Uint64", types.Typ[types.Uint64]
+	case kind == types.String:
+		op.writeMethod, op.writeArgType = "WriteString", typ
+		op.decMethod, op.decResultType = "Bytes", types.NewSlice(types.Typ[types.Byte])
+	default:
+		return nil, fmt.Errorf("rlp: type %v is not RLP-serializable", typ)
+	}
+	return op, nil
+}
+
+func (bctx *buildContext) makeByteSliceOp(typ types.Type) op {
+	return basicOp{
+		typ:           typ,
+		writeMethod:   "WriteBytes",
+		writeArgType:  types.NewSlice(types.Typ[types.Byte]),
+		decMethod:     "Bytes",
+		decResultType: types.NewSlice(types.Typ[types.Byte]),
+	}
+}
+
+func (op basicOp) genWrite(ctx *genContext, v string) string {
+	if !types.Identical(op.typ, op.writeArgType) {
+		v = ctx.typeString(op.writeArgType) + "(" + v + ")"
+	}
+	return fmt.Sprintf("w.%s(%s)\n", op.writeMethod, v)
+}
+
+func (op basicOp) genDecode(ctx *genContext) (string, string) {
+	var (
+		resultV = ctx.temp()
+		result  = resultV
+		method  = op.decMethod
+	)
+	if !types.Identical(op.typ, op.decResultType) {
+		// Value needs to be converted.
+		result = ctx.typeString(op.typ) + "(" + resultV + ")"
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s, err := dec.%s()\n", resultV, method)
+	fmt.Fprintf(&b, "if err != nil { return %s }\n", ctx.decodeError("err"))
+	return b.String(), result
+}
+
+// byteArrayOp handles [...]byte.
+type byteArrayOp struct {
+	typ types.Type
+}
+
+func (op byteArrayOp) genWrite(ctx *genContext, v string) string {
+	return fmt.Sprintf("w.WriteBytes(%s[:])\n", v)
+}
+
+func (op byteArrayOp) genDecode(ctx *genContext) (string, string) {
+	resultV := ctx.temp()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", resultV, ctx.typeString(op.typ))
+	fmt.Fprintf(&b, "if err := dec.ReadBytes(%s[:]); err != nil { return %s }\n", resultV, ctx.decodeError("err"))
+	return b.String(), resultV
+}
+
+// rawValueOp handles rlp.RawValue.
+type rawValueOp struct{}
+
+func (op rawValueOp) genWrite(ctx *genContext, v string) string {
+	return fmt.Sprintf("w.Write(%s)\n", v)
+}
+
+func (op rawValueOp) genDecode(ctx *genContext) (string, string) {
+	resultV := ctx.temp()
+	code := fmt.Sprintf("%s, err := dec.Raw()\nif err != nil { return %s }\n", resultV, ctx.decodeError("err"))
+	return code, ctx.rlp("RawValue") + "(" + resultV + ")"
+}
+
+// bigIntOp handles big.Int.
+// This exists because big.Int has it's own decoder operation on rlp.Stream,
+// but the decode method returns *big.Int, so it needs to be dereferenced.
+type bigIntOp struct {
+	pointer bool
+}
+
+func (op bigIntOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+
+	if op.pointer {
+		fmt.Fprintf(&b, "if %s == nil {\n", v)
+		fmt.Fprintf(&b, "  w.Write(%s)\n", ctx.rlp("EmptyString"))
+		fmt.Fprintf(&b, "} else {\n")
+		fmt.Fprintf(&b, "  if %s.Sign() == -1 {\n", v)
+		fmt.Fprintf(&b, "    return %s\n", ctx.encodeError(ctx.rlp("ErrNegativeBigInt")))
+		fmt.Fprintf(&b, "  }\n")
+		fmt.Fprintf(&b, "  w.WriteBigInt(%s)\n", v)
+		fmt.Fprintf(&b, "}\n")
+	} else {
+		fmt.Fprintf(&b, "if %s.Sign() == -1 {\n", v)
+		fmt.Fprintf(&b, "  return %s\n", ctx.encodeError(ctx.rlp("ErrNegativeBigInt")))
+		fmt.Fprintf(&b, "}\n")
+		fmt.Fprintf(&b, "w.WriteBigInt(&%s)\n", v)
+	}
+	return b.String()
+}
+
+func (op bigIntOp) genDecode(ctx *genContext) (string, string) {
+	var resultV = ctx.temp()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s, err := dec.BigInt()\n", resultV)
+	fmt.Fprintf(&b, "if err != nil { return %s }\n", ctx.decodeError("err"))
+
+	result := resultV
+	if !op.pointer {
+		result = "(*" + resultV + ")"
+	}
+	return b.String(), result
+}
+
+// uint256Op handles u256.Int.
+type uint256Op struct {
+	typ     types.Type // the u256.Int type
+	pointer bool
+}
+
+func (op uint256Op) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+
+	if op.pointer {
+		fmt.Fprintf(&b, "if %s == nil {\n", v)
+		fmt.Fprintf(&b, "  w.Write(%s)\n", ctx.rlp("EmptyString"))
+		fmt.Fprintf(&b, "} else {\n")
+		fmt.Fprintf(&b, "  w.WriteUint256(%s)\n", v)
+		fmt.Fprintf(&b, "}\n")
+	} else {
+		fmt.Fprintf(&b, "w.WriteUint256(&%s)\n", v)
+	}
+	return b.String()
+}
+
+func (op uint256Op) genDecode(ctx *genContext) (string, string) {
+	var resultV = ctx.temp()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", resultV, ctx.typeString(op.typ))
+	fmt.Fprintf(&b, "if err := dec.ReadUint256(&%s); err != nil { return %s }\n", resultV, ctx.decodeError("err"))
+
+	result := resultV
+	if op.pointer {
+		result = "&" + resultV
+	}
+	return b.String(), result
+}
+
+// encoderDecoderOp handles types implementing rlp.Encoder or rlp.Decoder.
+// The side that isn't implemented by the type goes through package rlp,
+// which applies the regular rules for the type's kind. This is also used
+// for interfaces, passing a pointer to the interface value lets package rlp
+// find the implementations registered for the interface type.
+type encoderDecoderOp struct {
+	typ     types.Type
+	encoder bool
+	decoder bool
+}
+
+func (op encoderDecoderOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+	switch {
+	case op.encoder:
+		fmt.Fprintf(&b, "if err := %s.EncodeRLP(w); err != nil {\n", v)
+	default:
+		fmt.Fprintf(&b, "if err := %s(w, &%s); err != nil {\n", ctx.rlp("Encode"), v)
+	}
+	fmt.Fprintf(&b, "  return %s\n", ctx.encodeError("err"))
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+func (op encoderDecoderOp) genDecode(ctx *genContext) (string, string) {
+	resultV := ctx.temp()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", resultV, ctx.typeString(op.typ))
+	if op.decoder {
+		fmt.Fprintf(&b, "if err := %s.DecodeRLP(dec); err != nil {\n", resultV)
+	} else {
+		fmt.Fprintf(&b, "if err := dec.Decode(&%s); err != nil {\n", resultV)
+	}
+	fmt.Fprintf(&b, "  return %s\n", ctx.decodeError("err"))
+	fmt.Fprintf(&b, "}\n")
+	return b.String(), resultV
+}
+
+// ptrOp handles pointer types.
+type ptrOp struct {
+	elemTyp  types.Type
+	elem     op
+	nilOK    bool
+	nilValue rlpstruct.NilKind
+}
+
+func (bctx *buildContext) makePtrOp(elemTyp types.Type, tags rlpstruct.Tags) (op, error) {
+	elemOp, err := bctx.makeOp(elemTyp, rlpstruct.Tags{})
+	if err != nil {
+		return nil, err
+	}
+	op := ptrOp{elemTyp: elemTyp, elem: elemOp}
+
+	// Determine nil value.
+	if tags.NilOK {
+		op.nilOK = true
+		op.nilValue = tags.NilKind
+	} else {
+		styp := bctx.typeToStructType(elemTyp)
+		op.nilValue = styp.DeaultNilValue()
+	}
+	return op, nil
+}
+
+func (op ptrOp) genWrite(ctx *genContext, v string) string {
+	// Note: in writer functions, accesses to v are read-only, i.e. v is any Go
+	// expression. To make all accesses work through the pointer, we substitute
+	// v with (*v). This is required for most accesses including `v`, `call(v)`,
+	// and `v[index]` on slices.
+	//
+	// For `v.field` and `v[:]` on arrays, the dereference operation is not required.
+	var vv string
+	_, isStruct := op.elem.(structOp)
+	_, isByteArray := op.elem.(byteArrayOp)
+	if isStruct || isByteArray {
+		vv = v
+	} else {
+		vv = fmt.Sprintf("(*%s)", v)
+	}
+
+	nilValue := ctx.rlp("EmptyString")
+	if op.nilValue == rlpstruct.NilKindList {
+		nilValue = ctx.rlp("EmptyList")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "if %s == nil {\n", v)
+	fmt.Fprintf(&b, "  w.Write(%s)\n", nilValue)
+	fmt.Fprintf(&b, "} else {\n")
+	fmt.Fprintf(&b, "  %s", op.elem.genWrite(ctx, vv))
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+func (op ptrOp) genDecode(ctx *genContext) (string, string) {
+	result := ctx.temp()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s *%s\n", result, ctx.typeString(op.elemTyp))
+	if op.nilOK {
+		kind, size := ctx.temp(), ctx.temp()
+		fmt.Fprintf(&b, "if %s, %s, err := dec.Kind(); err != nil {\n", kind, size)
+		fmt.Fprintf(&b, "  return %s\n", ctx.decodeError("err"))
+		fmt.Fprintf(&b, "} else if %s != %s && %s == 0 {\n", kind, ctx.rlp("Byte"), size)
+		// Consume the empty value. This fails if the empty value has the wrong kind.
+		if op.nilValue == rlpstruct.NilKindList {
+			fmt.Fprintf(&b, "  if _, err := dec.List(); err != nil { return %s }\n", ctx.decodeError("err"))
+			fmt.Fprintf(&b, "  if err := dec.ListEnd(); err != nil { return %s }\n", ctx.decodeError("err"))
+		} else {
+			fmt.Fprintf(&b, "  if _, err := dec.Bytes(); err != nil { return %s }\n", ctx.decodeError("err"))
+		}
+		fmt.Fprintf(&b, "} else {\n")
+	} else {
+		fmt.Fprintf(&b, "{\n")
+	}
+	code, elemResult := op.elem.genDecode(ctx)
+	b.WriteString(code)
+	addr := ctx.addressOf(&b, elemResult)
+	fmt.Fprintf(&b, "%s = %s\n", result, addr)
+	fmt.Fprintf(&b, "}\n")
+	return b.String(), result
+}
+
+// structOp handles struct types.
+type structOp struct {
+	named          *types.Named // nil for anonymous structs
+	typ            types.Type
+	fields         []*structField
+	optionalFields []*structField
+}
+
+type structField struct {
+	name string
+	typ  types.Type
+	elem op
+	tail bool
+}
+
+func (bctx *buildContext) makeStructOp(named *types.Named, typ *types.Struct) (op, error) {
+	if named != nil {
+		bctx.building[named] = true
+		defer delete(bctx.building, named)
+	}
+	names, vars, tags, err := bctx.structFields(typ)
+	if err != nil {
+		return nil, err
+	}
+	op := structOp{named: named, typ: typ}
+	if named != nil {
+		op.typ = named
+	}
+	optional := false
+	for i, v := range vars {
+		elem, err := bctx.makeOp(v.Type(), tags[i])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", names[i], err)
+		}
+		f := &structField{name: names[i], typ: v.Type(), elem: elem, tail: tags[i].Tail}
+		optional = optional || tags[i].Optional
+		if optional {
+			// Everything after the first optional field, including a
+			// tail field, takes part in the trailing zero check.
+			op.optionalFields = append(op.optionalFields, f)
+		} else {
+			op.fields = append(op.fields, f)
+		}
+	}
+	return op, nil
+}
+
+func (op structOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+	var listMarker = ctx.temp()
+	fmt.Fprintf(&b, "%s := w.List()\n", listMarker)
+	for _, field := range op.fields {
+		selector := v + "." + field.name
+		ctx.pushField(field.name)
+		fmt.Fprint(&b, field.elem.genWrite(ctx, selector))
+		ctx.popPath(1)
+	}
+	op.writeOptionalFields(&b, ctx, v)
+	fmt.Fprintf(&b, "w.ListEnd(%s)\n", listMarker)
+	return b.String()
+}
+
+func (op structOp) writeOptionalFields(b *bytes.Buffer, ctx *genContext, v string) {
+	if len(op.optionalFields) == 0 {
+		return
+	}
+	// First check zero-ness of all optional fields.
+	var zeroV = make([]string, len(op.optionalFields))
+	for i, field := range op.optionalFields {
+		selector := v + "." + field.name
+		zeroV[i] = ctx.temp()
+		fmt.Fprintf(b, "%s := %s\n", zeroV[i], ctx.nonZero(selector, field.typ))
+	}
+	// Now write the fields. A field is written if it or any field
+	// following it is non-zero.
+	for i, field := range op.optionalFields {
+		selector := v + "." + field.name
+		cond := strings.Join(zeroV[i:], " || ")
+		fmt.Fprintf(b, "if %s {\n", cond)
+		ctx.pushField(field.name)
+		fmt.Fprint(b, field.elem.genWrite(ctx, selector))
+		ctx.popPath(1)
+		fmt.Fprintf(b, "}\n")
+	}
+}
+
+func (op structOp) genDecode(ctx *genContext) (string, string) {
+	// Get the string representation of the type.
+	// Here, named types are handled separately because the output
+	// would contain a copy of the struct definition otherwise.
+	var typeName string
+	if op.named != nil {
+		typeName = ctx.typeString(op.named)
+	} else {
+		typeName = ctx.typeString(op.typ)
+	}
+
+	// Create struct object.
+	var resultV = ctx.temp()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", resultV, typeName)
+
+	// Decode fields.
+	fmt.Fprintf(&b, "{\n")
+	fmt.Fprintf(&b, "if _, err := dec.List(); err != nil { return %s }\n", ctx.decodeError("err"))
+	for _, field := range op.fields {
+		op.decodeField(&b, ctx, resultV, field)
+	}
+	for _, field := range op.optionalFields {
+		if field.tail {
+			// The tail decoder loops until the list is exhausted.
+			op.decodeField(&b, ctx, resultV, field)
+			continue
+		}
+		fmt.Fprintf(&b, "if dec.MoreDataInList() {\n")
+		op.decodeField(&b, ctx, resultV, field)
+		fmt.Fprintf(&b, "}\n")
+	}
+	fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil { return %s }\n", ctx.decodeError("err"))
+	fmt.Fprintf(&b, "}\n")
+	return b.String(), resultV
+}
+
+func (op structOp) decodeField(b *bytes.Buffer, ctx *genContext, resultV string, field *structField) {
+	fmt.Fprintf(b, "// %s:\n", field.name)
+	ctx.pushField(field.name)
+	code, result := field.elem.genDecode(ctx)
+	ctx.popPath(1)
+	b.WriteString(code)
+	fmt.Fprintf(b, "%s.%s = %s\n", resultV, field.name, result)
+}
+
+// listOp handles slices and arrays of non-byte element types.
+type listOp struct {
+	typ     types.Type
+	elemTyp types.Type
+	elem    op
+	tail    bool
+	array   bool
+}
+
+func (bctx *buildContext) makeListOp(typ, elemTyp types.Type, array bool, tags rlpstruct.Tags) (op, error) {
+	elemOp, err := bctx.makeOp(elemTyp, rlpstruct.Tags{})
+	if err != nil {
+		return nil, err
+	}
+	return listOp{typ: typ, elemTyp: elemTyp, elem: elemOp, tail: tags.Tail, array: array}, nil
+}
+
+func (op listOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+	var listMarker, index string
+	if !op.tail {
+		listMarker = ctx.temp()
+		fmt.Fprintf(&b, "%s := w.List()\n", listMarker)
+	}
+	index = ctx.temp()
+	fmt.Fprintf(&b, "for %s := range %s {\n", index, v)
+	ctx.pushIndex(index)
+	fmt.Fprint(&b, op.elem.genWrite(ctx, v+"["+index+"]"))
+	ctx.popPath(3)
+	fmt.Fprintf(&b, "}\n")
+	if !op.tail {
+		fmt.Fprintf(&b, "w.ListEnd(%s)\n", listMarker)
+	}
+	return b.String()
+}
+
+func (op listOp) genDecode(ctx *genContext) (string, string) {
+	var resultV = ctx.temp()
+	var b bytes.Buffer
+
+	if op.array {
+		fmt.Fprintf(&b, "var %s %s\n", resultV, ctx.typeString(op.typ))
+		fmt.Fprintf(&b, "if _, err := dec.List(); err != nil { return %s }\n", ctx.decodeError("err"))
+		index := ctx.temp()
+		fmt.Fprintf(&b, "for %s := range %s {\n", index, resultV)
+		ctx.pushIndex(index)
+		code, result := op.elem.genDecode(ctx)
+		ctx.popPath(3)
+		b.WriteString(code)
+		fmt.Fprintf(&b, "%s[%s] = %s\n", resultV, index, result)
+		fmt.Fprintf(&b, "}\n")
+		fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil { return %s }\n", ctx.decodeError("err"))
+		return b.String(), resultV
+	}
+
+	// The reflection decoder never produces a nil slice for a list, mirror
+	// that here so re-encoding optional fields gives the same output. A tail
+	// is nil when there are no elements left, like in the reflection decoder.
+	if op.tail {
+		fmt.Fprintf(&b, "var %s %s\n", resultV, ctx.typeString(op.typ))
+	} else {
+		fmt.Fprintf(&b, "%s := %s{}\n", resultV, ctx.typeString(op.typ))
+		fmt.Fprintf(&b, "if _, err := dec.List(); err != nil { return %s }\n", ctx.decodeError("err"))
+	}
+	fmt.Fprintf(&b, "for dec.MoreDataInList() {\n")
+	ctx.pushIndex("len(" + resultV + ")")
+	code, result := op.elem.genDecode(ctx)
+	ctx.popPath(3)
+	b.WriteString(code)
+	fmt.Fprintf(&b, "%s = append(%s, %s)\n", resultV, resultV, result)
+	fmt.Fprintf(&b, "}\n")
+	if !op.tail {
+		fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil { return %s }\n", ctx.decodeError("err"))
+	}
+	return b.String(), resultV
+}
+
+// makeOp creates an op for the given type and tags. The cases are checked
+// in the same order as makeWriter and makeDecoder in package rlp.
+func (bctx *buildContext) makeOp(typ types.Type, tags rlpstruct.Tags) (op, error) {
+	switch {
+	case types.Identical(typ, bctx.rawValueType):
+		return rawValueOp{}, nil
+	case isBigInt(typ):
+		return bigIntOp{}, nil
+	case isUint256(typ):
+		return uint256Op{typ: typ}, nil
+	}
+	if ptr, ok := typ.(*types.Pointer); ok && isBigInt(ptr.Elem()) {
+		return bigIntOp{pointer: true}, nil
+	}
+	if ptr, ok := typ.(*types.Pointer); ok && isUint256(ptr.Elem()) {
+		return uint256Op{typ: ptr.Elem(), pointer: true}, nil
+	}
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		return bctx.makePtrOp(ptr.Elem(), tags)
+	}
+
+	named, _ := typ.(*types.Named)
+	if named != nil && named == bctx.topType {
+		if bctx.building[named] {
+			// Recursive reference to the type we're generating for. The
+			// generated methods will handle it.
+			return encoderDecoderOp{typ: typ, encoder: bctx.hasEncoder, decoder: bctx.hasDecoder}, nil
+		}
+	} else {
+		ptrTyp := types.NewPointer(typ)
+		enc, dec := bctx.isEncoder(ptrTyp), bctx.isDecoder(ptrTyp)
+		if enc || dec {
+			return encoderDecoderOp{typ: typ, encoder: enc, decoder: dec}, nil
+		}
+		if named != nil && bctx.building[named] {
+			// Recursive type, leave it to package rlp.
+			return encoderDecoderOp{typ: typ}, nil
+		}
+	}
+
+	switch utyp := typ.Underlying().(type) {
+	case *types.Basic:
+		return bctx.makeBasicOp(utyp)
+	case *types.Slice:
+		etyp := utyp.Elem()
+		if isByte(etyp) && !bctx.isEncoder(etyp) {
+			return bctx.makeByteSliceOp(typ), nil
+		}
+		return bctx.makeListOp(typ, etyp, false, tags)
+	case *types.Array:
+		etyp := utyp.Elem()
+		if isByte(etyp) && !bctx.isEncoder(etyp) {
+			return byteArrayOp{typ: typ}, nil
+		}
+		return bctx.makeListOp(typ, etyp, true, tags)
+	case *types.Struct:
+		return bctx.makeStructOp(named, utyp)
+	case *types.Interface:
+		if tags.Typed != "" {
+			return nil, fmt.Errorf(`rlp: "typed" tag is not supported by rlpgen`)
+		}
+		return encoderDecoderOp{typ: typ}, nil
+	case *types.Map:
+		if tags.Map {
+			return nil, fmt.Errorf(`rlp: "map" tag is not supported by rlpgen`)
+		}
+		return nil, fmt.Errorf("rlp: type %v is not RLP-serializable", typ)
+	default:
+		return nil, fmt.Errorf("rlp: type %v is not RLP-serializable", typ)
+	}
+}
+
+// generateDecoder generates the DecodeRLP method on 'typ'.
+func generateDecoder(ctx *genContext, typ string, op op) []byte {
+	ctx.resetTemp()
+	ctx.addImport(pathOfPackageRLP)
+
+	result, code := op.genDecode(ctx)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "func (obj *%s) DecodeRLP(dec *rlp.Stream) error {\n", typ)
+	fmt.Fprint(&b, result)
+	fmt.Fprintf(&b, "  *obj = %s\n", code)
+	fmt.Fprintf(&b, "  return nil\n")
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+// generateEncoder generates the EncodeRLP method on 'typ'.
+func generateEncoder(ctx *genContext, typ string, op op) []byte {
+	ctx.resetTemp()
+	ctx.addImport("io")
+	ctx.addImport(pathOfPackageRLP)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "func (obj *%s) EncodeRLP(_w io.Writer) error {\n", typ)
+	fmt.Fprintf(&b, "  w := rlp.NewEncoderBuffer(_w)\n")
+	fmt.Fprint(&b, op.genWrite(ctx, "obj"))
+	fmt.Fprintf(&b, "  return w.Flush()\n")
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+func (bctx *buildContext) generate(typ *types.Named, encoder, decoder bool) ([]byte, error) {
+	bctx.topType = typ
+
+	pkg := typ.Obj().Pkg()
+	op, err := bctx.makeStructOp(typ, typ.Underlying().(*types.Struct))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ctx       = newGenContext(pkg)
+		encSource []byte
+		decSource []byte
+	)
+	if encoder {
+		encSource = generateEncoder(ctx, typ.Obj().Name(), op)
+	}
+	if decoder {
+		decSource = generateDecoder(ctx, typ.Obj().Name(), op)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", pkg.Name())
+	for _, imp := range ctx.importsList() {
+		fmt.Fprintf(&b, "import %q\n", imp)
+	}
+	if encoder {
+		fmt.Fprintln(&b)
+		b.Write(encSource)
+	}
+	if decoder {
+		fmt.Fprintln(&b)
+		b.Write(decSource)
+	}
+
+	source := b.Bytes()
+	// fmt.Println(string(source))
+	return format.Source(source)
+}
@@ -0,0 +1,210 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+//go:build !norlpgen
+// +build !norlpgen
+
+package gentest
+
+import "awesomeProject/common"
+import "awesomeProject/rlp"
+import "io"
+import "strconv"
+
+func (obj *Mixed) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteBytes(obj.Hash[:])
+	if obj.Num == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.Num.Sign() == -1 {
+			return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Num")
+		}
+		w.WriteBigInt(obj.Num)
+	}
+	if obj.Ptr == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		w.WriteUint64((*obj.Ptr))
+	}
+	w.WriteBool(obj.Flag)
+	w.WriteString(obj.Name)
+	_tmp1 := w.List()
+	for _tmp2 := range obj.Inner {
+		_tmp3 := w.List()
+		w.WriteUint64(uint64(obj.Inner[_tmp2].X))
+		w.WriteBytes(obj.Inner[_tmp2].Y)
+		w.ListEnd(_tmp3)
+	}
+	w.ListEnd(_tmp1)
+	_tmp4 := w.List()
+	for _tmp5 := range obj.Arr {
+		_tmp6 := w.List()
+		w.WriteUint64(uint64(obj.Arr[_tmp5].X))
+		w.WriteBytes(obj.Arr[_tmp5].Y)
+		w.ListEnd(_tmp6)
+	}
+	w.ListEnd(_tmp4)
+	_tmp7 := obj.Extra != nil
+	if _tmp7 {
+		if obj.Extra == nil {
+			w.Write(rlp.EmptyList)
+		} else {
+			_tmp8 := w.List()
+			w.WriteUint64(uint64(obj.Extra.X))
+			w.WriteBytes(obj.Extra.Y)
+			w.ListEnd(_tmp8)
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Mixed) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp0 Mixed
+	{
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		// Hash:
+		var _tmp1 common.Hash
+		if err := dec.ReadBytes(_tmp1[:]); err != nil {
+			return rlp.WrapDecodeError(err, ".Hash")
+		}
+		_tmp0.Hash = _tmp1
+		// Num:
+		_tmp2, err := dec.BigInt()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Num")
+		}
+		_tmp0.Num = _tmp2
+		// Ptr:
+		var _tmp3 *uint64
+		if _tmp4, _tmp5, err := dec.Kind(); err != nil {
+			return rlp.WrapDecodeError(err, ".Ptr")
+		} else if _tmp4 != rlp.Byte && _tmp5 == 0 {
+			if _, err := dec.Bytes(); err != nil {
+				return rlp.WrapDecodeError(err, ".Ptr")
+			}
+		} else {
+			_tmp6, err := dec.Uint64()
+			if err != nil {
+				return rlp.WrapDecodeError(err, ".Ptr")
+			}
+			_tmp3 = &_tmp6
+		}
+		_tmp0.Ptr = _tmp3
+		// Flag:
+		_tmp7, err := dec.Bool()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Flag")
+		}
+		_tmp0.Flag = _tmp7
+		// Name:
+		_tmp8, err := dec.Bytes()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Name")
+		}
+		_tmp0.Name = string(_tmp8)
+		// Inner:
+		_tmp9 := []Inner{}
+		if _, err := dec.List(); err != nil {
+			return rlp.WrapDecodeError(err, ".Inner")
+		}
+		for dec.MoreDataInList() {
+			var _tmp10 Inner
+			{
+				if _, err := dec.List(); err != nil {
+					return rlp.WrapDecodeError(err, ".Inner["+strconv.Itoa(len(_tmp9))+"]")
+				}
+				// X:
+				_tmp11, err := dec.Uint16()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Inner["+strconv.Itoa(len(_tmp9))+"].X")
+				}
+				_tmp10.X = _tmp11
+				// Y:
+				_tmp12, err := dec.Bytes()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Inner["+strconv.Itoa(len(_tmp9))+"].Y")
+				}
+				_tmp10.Y = _tmp12
+				if err := dec.ListEnd(); err != nil {
+					return rlp.WrapDecodeError(err, ".Inner["+strconv.Itoa(len(_tmp9))+"]")
+				}
+			}
+			_tmp9 = append(_tmp9, _tmp10)
+		}
+		if err := dec.ListEnd(); err != nil {
+			return rlp.WrapDecodeError(err, ".Inner")
+		}
+		_tmp0.Inner = _tmp9
+		// Arr:
+		var _tmp13 [2]Inner
+		if _, err := dec.List(); err != nil {
+			return rlp.WrapDecodeError(err, ".Arr")
+		}
+		for _tmp14 := range _tmp13 {
+			var _tmp15 Inner
+			{
+				if _, err := dec.List(); err != nil {
+					return rlp.WrapDecodeError(err, ".Arr["+strconv.Itoa(_tmp14)+"]")
+				}
+				// X:
+				_tmp16, err := dec.Uint16()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Arr["+strconv.Itoa(_tmp14)+"].X")
+				}
+				_tmp15.X = _tmp16
+				// Y:
+				_tmp17, err := dec.Bytes()
+				if err != nil {
+					return rlp.WrapDecodeError(err, ".Arr["+strconv.Itoa(_tmp14)+"].Y")
+				}
+				_tmp15.Y = _tmp17
+				if err := dec.ListEnd(); err != nil {
+					return rlp.WrapDecodeError(err, ".Arr["+strconv.Itoa(_tmp14)+"]")
+				}
+			}
+			_tmp13[_tmp14] = _tmp15
+		}
+		if err := dec.ListEnd(); err != nil {
+			return rlp.WrapDecodeError(err, ".Arr")
+		}
+		_tmp0.Arr = _tmp13
+		if dec.MoreDataInList() {
+			// Extra:
+			var _tmp18 *Inner
+			{
+				var _tmp19 Inner
+				{
+					if _, err := dec.List(); err != nil {
+						return rlp.WrapDecodeError(err, ".Extra")
+					}
+					// X:
+					_tmp20, err := dec.Uint16()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Extra.X")
+					}
+					_tmp19.X = _tmp20
+					// Y:
+					_tmp21, err := dec.Bytes()
+					if err != nil {
+						return rlp.WrapDecodeError(err, ".Extra.Y")
+					}
+					_tmp19.Y = _tmp21
+					if err := dec.ListEnd(); err != nil {
+						return rlp.WrapDecodeError(err, ".Extra")
+					}
+				}
+				_tmp18 = &_tmp19
+			}
+			_tmp0.Extra = _tmp18
+		}
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	*obj = _tmp0
+	return nil
+}
@@ -0,0 +1,78 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+//go:build !norlpgen
+// +build !norlpgen
+
+package gentest
+
+import "awesomeProject/rlp"
+import "io"
+import "strconv"
+
+func (obj *OptionalTail) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteUint64(obj.A)
+	_tmp1 := obj.B != 0
+	_tmp2 := obj.C != nil
+	_tmp3 := obj.Tail != nil
+	if _tmp1 || _tmp2 || _tmp3 {
+		w.WriteUint64(obj.B)
+	}
+	if _tmp2 || _tmp3 {
+		w.WriteBytes(obj.C)
+	}
+	if _tmp3 {
+		for _tmp4 := range obj.Tail {
+			w.WriteUint64(uint64(obj.Tail[_tmp4]))
+		}
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *OptionalTail) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp0 OptionalTail
+	{
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		// A:
+		_tmp1, err := dec.Uint64()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".A")
+		}
+		_tmp0.A = _tmp1
+		if dec.MoreDataInList() {
+			// B:
+			_tmp2, err := dec.Uint64()
+			if err != nil {
+				return rlp.WrapDecodeError(err, ".B")
+			}
+			_tmp0.B = _tmp2
+		}
+		if dec.MoreDataInList() {
+			// C:
+			_tmp3, err := dec.Bytes()
+			if err != nil {
+				return rlp.WrapDecodeError(err, ".C")
+			}
+			_tmp0.C = _tmp3
+		}
+		// Tail:
+		var _tmp4 []uint
+		for dec.MoreDataInList() {
+			_tmp5, err := dec.Uint64()
+			if err != nil {
+				return rlp.WrapDecodeError(err, ".Tail["+strconv.Itoa(len(_tmp4))+"]")
+			}
+			_tmp4 = append(_tmp4, uint(_tmp5))
+		}
+		_tmp0.Tail = _tmp4
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	*obj = _tmp0
+	return nil
+}
@@ -0,0 +1,86 @@
+// Code generated by rlpgen. DO NOT EDIT.
+
+//go:build !norlpgen
+// +build !norlpgen
+
+package gentest
+
+import "awesomeProject/rlp"
+import "io"
+
+func (obj *Positioned) EncodeRLP(_w io.Writer) error {
+	w := rlp.NewEncoderBuffer(_w)
+	_tmp0 := w.List()
+	w.WriteUint64(obj.Nonce)
+	w.WriteBytes(obj.Data)
+	if obj.Sig.V == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.Sig.V.Sign() == -1 {
+			return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Sig.V")
+		}
+		w.WriteBigInt(obj.Sig.V)
+	}
+	if obj.Sig.R == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.Sig.R.Sign() == -1 {
+			return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Sig.R")
+		}
+		w.WriteBigInt(obj.Sig.R)
+	}
+	if obj.Sig.S == nil {
+		w.Write(rlp.EmptyString)
+	} else {
+		if obj.Sig.S.Sign() == -1 {
+			return rlp.WrapEncodeError(rlp.ErrNegativeBigInt, ".Sig.S")
+		}
+		w.WriteBigInt(obj.Sig.S)
+	}
+	w.ListEnd(_tmp0)
+	return w.Flush()
+}
+
+func (obj *Positioned) DecodeRLP(dec *rlp.Stream) error {
+	var _tmp0 Positioned
+	{
+		if _, err := dec.List(); err != nil {
+			return err
+		}
+		// Nonce:
+		_tmp1, err := dec.Uint64()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Nonce")
+		}
+		_tmp0.Nonce = _tmp1
+		// Data:
+		_tmp2, err := dec.Bytes()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Data")
+		}
+		_tmp0.Data = _tmp2
+		// Sig.V:
+		_tmp3, err := dec.BigInt()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Sig.V")
+		}
+		_tmp0.Sig.V = _tmp3
+		// Sig.R:
+		_tmp4, err := dec.BigInt()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Sig.R")
+		}
+		_tmp0.Sig.R = _tmp4
+		// Sig.S:
+		_tmp5, err := dec.BigInt()
+		if err != nil {
+			return rlp.WrapDecodeError(err, ".Sig.S")
+		}
+		_tmp0.Sig.S = _tmp5
+		if err := dec.ListEnd(); err != nil {
+			return err
+		}
+	}
+	*obj = _tmp0
+	return nil
+}
@@ -0,0 +1,182 @@
+package gentest
+
+import (
+	"awesomeProject/common"
+	"awesomeProject/rlp"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The reflect* types have the same layout as the generated types, but no
+// methods, so package rlp uses reflection for them.
+type (
+	reflectOptionalTail OptionalTail
+	reflectMixed        Mixed
+	reflectPositioned   Positioned
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestOptionalTailDecode(t *testing.T) {
+	inputs := []string{
+		"C101",             // B, C missing
+		"C20102",           // C missing
+		"C4010283FFFFFF",   // no tail
+		"C6010283FFFFFF05", // one tail element
+		"C7010283FFFFFF0506",
+		"C3018080", // zero values present
+		"C0",       // A missing
+		"C40102C003",
+	}
+	for _, input := range inputs {
+		var gen OptionalTail
+		genErr := rlp.DecodeBytes(unhex(input), &gen)
+		var refl OptionalTail
+		reflErr := rlp.DecodeBytes(unhex(input), (*reflectOptionalTail)(&refl))
+		checkSame(t, input, gen, genErr, refl, reflErr)
+		if genErr != nil {
+			continue
+		}
+		// Re-encoding must give the same output for both.
+		genEnc, _ := rlp.EncodeToBytes(&gen)
+		reflEnc, _ := rlp.EncodeToBytes((*reflectOptionalTail)(&refl))
+		if !bytes.Equal(genEnc, reflEnc) {
+			t.Errorf("input %s: re-encoding mismatch: generated %x, reflection %x", input, genEnc, reflEnc)
+		}
+	}
+}
+
+func TestOptionalTailEncode(t *testing.T) {
+	values := []OptionalTail{
+		{},
+		{A: 1},
+		{A: 1, Tail: []uint{}},
+		{A: 1, Tail: []uint{2}},
+		{A: 1, C: []byte{}},
+		{A: 1, B: 2, C: []byte{3}, Tail: []uint{4, 5}},
+	}
+	for _, v := range values {
+		checkEncode(t, &v, (*reflectOptionalTail)(&v))
+	}
+}
+
+func TestMixed(t *testing.T) {
+	ptr := uint64(7)
+	values := []Mixed{
+		{},
+		{Num: big.NewInt(0), Ptr: &ptr, Inner: []Inner{}},
+		{
+			Hash:    common.Hash{1, 2, 3},
+			Num:     new(big.Int).Lsh(big.NewInt(1), 100),
+			Flag:    true,
+			Name:    "name",
+			Inner:   []Inner{{X: 1}, {X: 2, Y: []byte{3}}},
+			Arr:     [2]Inner{{X: 4}, {Y: make([]byte, 60)}},
+			private: 9,
+			Ignored: 10,
+			Extra:   &Inner{X: 11},
+		},
+	}
+	for _, v := range values {
+		enc := checkEncode(t, &v, (*reflectMixed)(&v))
+
+		var gen Mixed
+		genErr := rlp.DecodeBytes(enc, &gen)
+		var refl Mixed
+		reflErr := rlp.DecodeBytes(enc, (*reflectMixed)(&refl))
+		checkSame(t, hex.EncodeToString(enc), gen, genErr, refl, reflErr)
+	}
+}
+
+func TestMixedDecodeErrorPath(t *testing.T) {
+	v := Mixed{Num: big.NewInt(0), Inner: []Inner{{X: 1}, {X: 0x4321}}}
+	enc, err := rlp.EncodeToBytes(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Give Inner[1].X a leading zero byte.
+	if bytes.Count(enc, unhex("824321")) != 1 {
+		t.Fatalf("can't locate Inner[1].X in %x", enc)
+	}
+	enc = bytes.Replace(enc, unhex("824321"), unhex("820043"), 1)
+
+	var gen Mixed
+	genErr := rlp.DecodeBytes(enc, &gen)
+	var refl Mixed
+	reflErr := rlp.DecodeBytes(enc, (*reflectMixed)(&refl))
+	const path = ".Inner[1].X"
+	if genErr == nil || !strings.HasSuffix(genErr.Error(), "gentest.Mixed"+path) {
+		t.Errorf("generated decoder: wrong error %v", genErr)
+	}
+	if reflErr == nil || !strings.HasSuffix(reflErr.Error(), "gentest.reflectMixed"+path) {
+		t.Errorf("reflection decoder: wrong error %v", reflErr)
+	}
+	if !errors.Is(genErr, rlp.ErrCanonInt) {
+		t.Errorf("generated decoder: error %v does not wrap ErrCanonInt", genErr)
+	}
+}
+
+func TestPositioned(t *testing.T) {
+	v := Positioned{Sig: Sig{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, Nonce: 7, Data: []byte{1}}
+	enc := checkEncode(t, &v, (*reflectPositioned)(&v))
+	if want := unhex("C50701010203"); !bytes.Equal(enc, want) {
+		t.Fatalf("wrong encoding %x, want %x", enc, want)
+	}
+	var gen Positioned
+	genErr := rlp.DecodeBytes(enc, &gen)
+	var refl Positioned
+	reflErr := rlp.DecodeBytes(enc, (*reflectPositioned)(&refl))
+	checkSame(t, hex.EncodeToString(enc), gen, genErr, refl, reflErr)
+
+	// Negative integers are reported with the same path.
+	v.R = big.NewInt(-1)
+	_, genErr = rlp.EncodeToBytes(&v)
+	_, reflErr = rlp.EncodeToBytes((*reflectPositioned)(&v))
+	var genEncErr, reflEncErr *rlp.EncodeError
+	if !errors.As(genErr, &genEncErr) || !errors.As(reflErr, &reflEncErr) || genEncErr.Path != reflEncErr.Path {
+		t.Errorf("error mismatch: generated %v, reflection %v", genErr, reflErr)
+	}
+	if want := "Positioned.Sig.R: rlp: cannot encode negative big.Int"; genErr == nil || genErr.Error() != want {
+		t.Errorf("generated encoder: got error %v, want %s", genErr, want)
+	}
+	if want := "reflectPositioned.Sig.R: rlp: cannot encode negative big.Int"; reflErr == nil || reflErr.Error() != want {
+		t.Errorf("reflection encoder: got error %v, want %s", reflErr, want)
+	}
+}
+
+// checkEncode encodes a value through generated and reflection code and
+// returns the encoding.
+func checkEncode(t *testing.T, gen, refl interface{}) []byte {
+	t.Helper()
+	genEnc, genErr := rlp.EncodeToBytes(gen)
+	reflEnc, reflErr := rlp.EncodeToBytes(refl)
+	if (genErr == nil) != (reflErr == nil) {
+		t.Fatalf("%+v: error mismatch: generated %v, reflection %v", gen, genErr, reflErr)
+	}
+	if !bytes.Equal(genEnc, reflEnc) {
+		t.Errorf("%+v: encoding mismatch:\ngenerated  %x\nreflection %x", gen, genEnc, reflEnc)
+	}
+	return genEnc
+}
+
+func checkSame(t *testing.T, input string, gen interface{}, genErr error, refl interface{}, reflErr error) {
+	t.Helper()
+	if (genErr == nil) != (reflErr == nil) {
+		t.Errorf("input %s: error mismatch: generated %v, reflection %v", input, genErr, reflErr)
+		return
+	}
+	if genErr == nil && !reflect.DeepEqual(gen, refl) {
+		t.Errorf("input %s: value mismatch:\ngenerated  %#v\nreflection %#v", input, gen, refl)
+	}
+}
@@ -0,0 +1,54 @@
+// Package gentest holds types with rlpgen-generated methods. Its tests
+// compare the generated code with the reflection-based encoder and decoder.
+package gentest
+
+import (
+	"awesomeProject/common"
+	"math/big"
+)
+
+//go:generate go run .. -type OptionalTail -out gen_optionaltail_rlp.go
+
+// OptionalTail has optional fields followed by a tail.
+type OptionalTail struct {
+	A    uint64
+	B    uint64 `rlp:"optional"`
+	C    []byte `rlp:"optional"`
+	Tail []uint `rlp:"tail"`
+}
+
+//go:generate go run .. -type Mixed -out gen_mixed_rlp.go
+
+// Mixed covers the other field kinds supported by rlpgen.
+type Mixed struct {
+	Hash    common.Hash
+	Num     *big.Int
+	Ptr     *uint64 `rlp:"nil"`
+	Flag    bool
+	Name    string
+	Inner   []Inner
+	Arr     [2]Inner
+	private uint
+	Ignored uint   `rlp:"-"`
+	Extra   *Inner `rlp:"optional"`
+}
+
+// Inner is a nested struct without generated methods.
+type Inner struct {
+	X uint16
+	Y []byte
+}
+
+//go:generate go run .. -type Positioned -out gen_positioned_rlp.go
+
+// Positioned has an inlined struct and explicit field positions.
+type Positioned struct {
+	Sig   `rlp:"inline,pos=2"`
+	Nonce uint64 `rlp:"pos=0"`
+	Data  []byte `rlp:"pos=1"`
+}
+
+// Sig is inlined into Positioned.
+type Sig struct {
+	V, R, S *big.Int
+}
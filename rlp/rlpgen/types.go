@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+)
+
+// typeReflectKind gives the reflect.Kind that represents typ.
+func typeReflectKind(typ types.Type) reflect.Kind {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		k := typ.Kind()
+		if k >= types.Bool && k <= types.Complex128 {
+			// value order matches for Bool..Complex128
+			return reflect.Bool + reflect.Kind(k-types.Bool)
+		}
+		if k == types.String {
+			return reflect.String
+		}
+		if k == types.UnsafePointer {
+			return reflect.UnsafePointer
+		}
+		panic(fmt.Errorf("unhandled BasicKind %v", k))
+	case *types.Array:
+		return reflect.Array
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Map:
+		return reflect.Map
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Signature:
+		return reflect.Func
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Struct:
+		return reflect.Struct
+	default:
+		panic(fmt.Errorf("unhandled type %T", typ))
+	}
+}
+
+// nonZeroCheck returns the expression checking whether v is non-zero.
+// It mirrors reflect.Value.IsZero, which the reflection encoder uses to
+// decide whether trailing optional fields are written.
+func nonZeroCheck(v string, typ types.Type, qualify types.Qualifier) string {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return v + " != nil"
+	}
+	if b, ok := typ.Underlying().(*types.Basic); ok {
+		switch {
+		case b.Info()&types.IsBoolean != 0:
+			return v
+		case b.Info()&types.IsString != 0:
+			return v + ` != ""`
+		default:
+			return v + " != 0"
+		}
+	}
+	if types.Comparable(typ) {
+		return fmt.Sprintf("%s != (%s{})", v, types.TypeString(typ, qualify))
+	}
+	return fmt.Sprintf("!reflect.ValueOf(%s).IsZero()", v)
+}
+
+// isBigInt checks whether 'typ' is "math/big".Int.
+func isBigInt(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	name := named.Obj()
+	return name.Pkg() != nil && name.Pkg().Path() == "math/big" && name.Name() == "Int"
+}
+
+// isUint256 checks whether 'typ' is "awesomeProject/common/u256".Int.
+func isUint256(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	name := named.Obj()
+	return name.Pkg() != nil && name.Pkg().Path() == "awesomeProject/common/u256" && name.Name() == "Int"
+}
+
+// isByte checks whether the underlying type of 'typ' is uint8.
+func isByte(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Uint8
+}
+
+// lookupStructType finds the named struct type with the given name.
+func lookupStructType(scope *types.Scope, name string) (*types.Named, error) {
+	typ, err := lookupType(scope, name)
+	if err != nil {
+		return nil, err
+	}
+	_, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("not a struct type")
+	}
+	return typ, nil
+}
+
+// lookupType looks up the named type with the given name.
+func lookupType(scope *types.Scope, name string) (*types.Named, error) {
+	obj := scope.Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("no such identifier")
+	}
+	typ, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("not a type")
+	}
+	named, ok := typ.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("not a named type")
+	}
+	return named, nil
+}
@@ -0,0 +1,248 @@
+// Package rlptest runs the Ethereum RLP conformance test vectors against
+// package rlp.
+//
+// A vector file is a JSON object mapping test names to test cases. Each case
+// has an "in" value and the expected hex-encoded RLP output "out":
+//
+//	{"dog": {"in": "dog", "out": "0x83646f67"}}
+//
+// Strings starting with '#' in the input are big integers in decimal
+// notation. An "in" value of "INVALID" marks an output that must be
+// rejected by the decoder, "VALID" one that must be accepted.
+package rlptest
+
+import (
+	"awesomeProject/rlp"
+	"bytes"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// vectors holds the standard test vectors shipped with this package.
+//
+//go:embed testdata/*.json
+var vectors embed.FS
+
+// Test is a single RLP test case.
+type Test struct {
+	// If the value of In is "INVALID" or "VALID", the test
+	// checks whether Out can be decoded into a value of
+	// type interface{}.
+	//
+	// For other JSON values, In is treated as a driver for
+	// calls to rlp.Stream. The test also verifies that encoding
+	// In produces the bytes in Out.
+	In interface{} `json:"in"`
+
+	// Out is a hex-encoded RLP value.
+	Out string `json:"out"`
+}
+
+// Result is the outcome of running a named test.
+type Result struct {
+	Name string
+	Err  error // nil if the test passed
+}
+
+// LoadFile reads a JSON vector file.
+func LoadFile(file string) (map[string]*Test, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// Load parses the content of a JSON vector file.
+func Load(data []byte) (map[string]*Test, error) {
+	var tests map[string]*Test
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers exact, test inputs go beyond what float64 can hold.
+	dec.UseNumber()
+	if err := dec.Decode(&tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// Builtin returns the standard test vectors shipped with this package. Test
+// names are prefixed with the name of the file they come from.
+func Builtin() (map[string]*Test, error) {
+	files, err := vectors.ReadDir("testdata")
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]*Test)
+	for _, f := range files {
+		data, err := vectors.ReadFile(path.Join("testdata", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tests, err := Load(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name(), err)
+		}
+		prefix := strings.TrimSuffix(f.Name(), ".json")
+		for name, t := range tests {
+			all[prefix+"/"+name] = t
+		}
+	}
+	return all, nil
+}
+
+// RunAll runs all tests in the map. Results are sorted by test name.
+func RunAll(tests map[string]*Test) []Result {
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	for i, name := range names {
+		results[i] = Result{Name: name, Err: tests[name].Run()}
+	}
+	return results
+}
+
+// FromHex decodes a hex string with optional 0x prefix.
+func FromHex(s string) ([]byte, error) {
+	if len(s) > 1 && (s[0:2] == "0x" || s[0:2] == "0X") {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
+}
+
+// Run executes the test.
+func (t *Test) Run() error {
+	outb, err := FromHex(t.Out)
+	if err != nil {
+		return fmt.Errorf("invalid hex in Out")
+	}
+
+	// Handle simple decoding tests with no actual In value.
+	if t.In == "VALID" || t.In == "INVALID" {
+		return checkDecodeInterface(outb, t.In == "VALID")
+	}
+
+	// Check whether encoding the value produces the same bytes.
+	in, err := translateJSON(t.In)
+	if err != nil {
+		return err
+	}
+	b, err := rlp.EncodeToBytes(in)
+	if err != nil {
+		return fmt.Errorf("encode failed: %v", err)
+	}
+	if !bytes.Equal(b, outb) {
+		return fmt.Errorf("encode produced %x, want %x", b, outb)
+	}
+	// Test stream decoding.
+	s := rlp.NewStream(bytes.NewReader(outb), 0)
+	return checkDecodeFromJSON(s, in)
+}
+
+func checkDecodeInterface(b []byte, isValid bool) error {
+	err := rlp.DecodeBytes(b, new(interface{}))
+	switch {
+	case isValid && err != nil:
+		return fmt.Errorf("decoding failed: %v", err)
+	case !isValid && err == nil:
+		return errors.New("decoding of invalid value succeeded")
+	}
+	return nil
+}
+
+// translateJSON makes test json values encodable with RLP.
+func translateJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("unsupported integer %s in input", v)
+		}
+		return uint64(i), nil
+	case string:
+		if len(v) > 0 && v[0] == '#' { // # starts a faux big int.
+			big, ok := new(big.Int).SetString(v[1:], 10)
+			if !ok {
+				return nil, fmt.Errorf("bad test: bad big int: %q", v)
+			}
+			return big, nil
+		}
+		return []byte(v), nil
+	case []interface{}:
+		new := make([]interface{}, len(v))
+		for i := range v {
+			var err error
+			if new[i], err = translateJSON(v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return new, nil
+	default:
+		return nil, fmt.Errorf("can't handle %T", v)
+	}
+}
+
+// checkDecodeFromJSON decodes from s guided by exp. exp drives the
+// Stream by invoking decoding operations (Uint, Big, List, ...) based on
+// the type of each value. The value decoded from the RLP stream must
+// match the JSON value.
+func checkDecodeFromJSON(s *rlp.Stream, exp interface{}) error {
+	switch exp := exp.(type) {
+	case uint64:
+		i, err := s.Uint64()
+		if err != nil {
+			return addStack("Uint", exp, err)
+		}
+		if i != exp {
+			return addStack("Uint", exp, fmt.Errorf("result mismatch: got %d", i))
+		}
+	case *big.Int:
+		big := new(big.Int)
+		if err := s.Decode(&big); err != nil {
+			return addStack("Big", exp, err)
+		}
+		if big.Cmp(exp) != 0 {
+			return addStack("Big", exp, fmt.Errorf("result mismatch: got %d", big))
+		}
+	case []byte:
+		b, err := s.Bytes()
+		if err != nil {
+			return addStack("Bytes", exp, err)
+		}
+		if !bytes.Equal(b, exp) {
+			return addStack("Bytes", exp, fmt.Errorf("result mismatch: got %x", b))
+		}
+	case []interface{}:
+		if _, err := s.List(); err != nil {
+			return addStack("List", exp, err)
+		}
+		for i, v := range exp {
+			if err := checkDecodeFromJSON(s, v); err != nil {
+				return addStack(fmt.Sprintf("[%d]", i), exp, err)
+			}
+		}
+		if err := s.ListEnd(); err != nil {
+			return addStack("ListEnd", exp, err)
+		}
+	default:
+		panic(fmt.Errorf("unhandled type: %T", exp))
+	}
+	return nil
+}
+
+func addStack(op string, val interface{}, err error) error {
+	lines := strings.Split(err.Error(), "\n")
+	lines = append(lines, fmt.Sprintf("\t%s: %v", op, val))
+	return errors.New(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,47 @@
+package rlptest
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	tests, err := Builtin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) == 0 {
+		t.Fatal("no builtin test vectors")
+	}
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		test := tests[name]
+		t.Run(name, func(t *testing.T) {
+			if err := test.Run(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestRunFailures checks that broken vectors are reported.
+func TestRunFailures(t *testing.T) {
+	tests, err := Load([]byte(`{
+		"wrongOut":     {"in": "dog", "out": "0x83646f68"},
+		"badHex":       {"in": "dog", "out": "0xzz"},
+		"invalidValid": {"in": "INVALID", "out": "0x83646f67"},
+		"validInvalid": {"in": "VALID", "out": "0xc1"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range RunAll(tests) {
+		if r.Err == nil {
+			t.Errorf("%s: expected error", r.Name)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build purego || nounsafe
+// +build purego nounsafe
+
+package rlp
+
+import "reflect"
+
+// byteArrayBytes returns a slice of the byte array v, which must be
+// addressable.
+func byteArrayBytes(value reflect.Value, length int) []byte {
+	return value.Slice(0, length).Bytes()
+}
@@ -0,0 +1,7 @@
+//go:build purego || nounsafe
+// +build purego nounsafe
+
+package rlp
+
+// byteArrayImpl names the byteArrayBytes implementation in benchmark names.
+const byteArrayImpl = "purego"
@@ -15,12 +15,13 @@ type typekey struct {
 }
 
 type field struct {
-	index    int
+	index    []int
+	name     string // selector of the field, e.g. "Sig.R" for inlined structs
 	info     *typeinfo
 	optional bool //不知道干嘛的
 }
 
-type decoder func()
+type decoder func(*Stream, reflect.Value) error
 
 type writer func(reflect.Value, *encBuffer) error
 
@@ -29,6 +30,7 @@ var theTC = newTypeCache()
 func newTypeCache() *typeCache {
 	tempTC := new(typeCache)
 	tempTC.cur.Store(make(map[typekey]*typeinfo))
+	tempTC.values.Store(new(sync.Map))
 	return tempTC
 }
 
@@ -41,12 +43,12 @@ type typeinfo struct {
 
 type structFieldError struct {
 	typ   reflect.Type
-	field int
+	field []int
 	err   error
 }
 
 func (e structFieldError) Error() string {
-	return fmt.Sprintf("%v (struct field %v.%s)", e.err, e.typ, e.typ.Field(e.field).Name)
+	return fmt.Sprintf("%v (struct field %v.%s)", e.err, e.typ, fieldName(e.typ, e.field))
 }
 
 type typeCache struct {
@@ -56,6 +58,16 @@ type typeCache struct {
 	// This lock synchronizes writers.
 	mu   sync.Mutex
 	next map[typekey]*typeinfo
+
+	// codecs and typed hold the registered codecs and interface
+	// implementations. They are only accessed with mu held.
+	codecs map[reflect.Type]*codec
+	typed  map[string]*typedInterface
+
+	// values holds the Codecs of EncodeValue and DecodeValue, a *sync.Map
+	// from reflect.Type to *Codec[T]. Like cur, it is replaced when codecs
+	// or interface implementations are registered.
+	values atomic.Value
 }
 
 func (c *typeCache) info(typ reflect.Type) *typeinfo {
@@ -101,48 +113,41 @@ func (c *typeCache) infoWhileGenerating(typ reflect.Type, tags rlpstruct.Tags) *
 	// the dummy value and won't call itself recursively.
 	info := new(typeinfo)
 	c.next[key] = info
-	info.generate(typ, tags)
+	if cd := c.codecs[typ]; cd != nil {
+		info.generateCodec(typ, tags, cd)
+	} else {
+		info.generate(typ, tags)
+	}
 	return info
 }
 
 func (i *typeinfo) generate(typ reflect.Type, tags rlpstruct.Tags) {
-	//i.decoder, i.decoderErr = makeDecoder(typ, tags)
+	i.decoder, i.decoderErr = makeDecoder(typ, tags)
 	i.writer, i.writerErr = makeWriter(typ, tags)
 }
 
+func cachedDecoder(typ reflect.Type) (decoder, error) {
+	info := theTC.info(typ)
+	return info.decoder, info.decoderErr
+}
+
 func cachedWriter(typ reflect.Type) (writer, error) {
 	info := theTC.info(typ)
 	return info.writer, info.writerErr
 }
 
 func structFlieds(p reflect.Type) (fields []*field, err error) {
-	//为什么要先转为rlpstruct.Field类型，Field类型有什么用
-	var allStructFields []rlpstruct.Field
-	for i := 0; i < p.NumField(); i++ {
-		rf := p.Field(i)
-		allStructFields = append(allStructFields, rlpstruct.Field{
-			Name:     rf.Name,
-			Index:    i,
-			Exported: rf.PkgPath == "",
-			Type:     *rtypeToStructType(rf.Type, nil),
-			Tag:      string(rf.Tag),
-		})
-	}
-	structFields, structTags, err := rlpstruct.ProcessFields(allStructFields)
+	structFields, structTags, err := processStructFields(p)
 	if err != nil {
-		if tagErr, ok := err.(rlpstruct.TagError); ok {
-			tagErr.StructType = p.String()
-			return nil, tagErr
-		}
 		return nil, err
 	}
-
 	for i, sf := range structFields {
-		typ := p.Field(sf.Index).Type
+		typ := p.FieldByIndex(sf.Path).Type
 		tag := structTags[i]
 		info := theTC.infoWhileGenerating(typ, tag)
 		fields = append(fields, &field{
-			index:    sf.Index,
+			index:    sf.Path,
+			name:     fieldName(p, sf.Path),
 			info:     info,
 			optional: tag.Optional,
 		})
@@ -150,6 +155,59 @@ func structFlieds(p reflect.Type) (fields []*field, err error) {
 	return fields, nil
 }
 
+// processStructFields returns the encoded fields of struct type p and
+// their tags.
+func processStructFields(p reflect.Type) ([]rlpstruct.Field, []rlpstruct.Tags, error) {
+	//为什么要先转为rlpstruct.Field类型，Field类型有什么用
+	allStructFields := rtypeToStructFields(p)
+	structFields, structTags, err := rlpstruct.ProcessFields(allStructFields)
+	if err != nil {
+		if tagErr, ok := err.(rlpstruct.TagError); ok {
+			tagErr.StructType = p.String()
+			return nil, nil, tagErr
+		}
+		return nil, nil, err
+	}
+	return structFields, structTags, nil
+}
+
+// rtypeToStructFields converts the fields of struct type p. The fields of
+// embedded structs are included for the rlp:"inline" tag.
+func rtypeToStructFields(p reflect.Type) []rlpstruct.Field {
+	var fields []rlpstruct.Field
+	for i := 0; i < p.NumField(); i++ {
+		rf := p.Field(i)
+		f := rlpstruct.Field{
+			Name:     rf.Name,
+			Index:    i,
+			Exported: rf.PkgPath == "",
+			Embedded: rf.Anonymous,
+			Type:     *rtypeToStructType(rf.Type, nil),
+			Tag:      string(rf.Tag),
+		}
+		if rf.Anonymous && rf.Type.Kind() == reflect.Struct {
+			f.Fields = rtypeToStructFields(rf.Type)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fieldName returns the selector of the field with the given index sequence
+// in struct type p.
+func fieldName(p reflect.Type, index []int) string {
+	name := ""
+	for i, x := range index {
+		f := p.Field(x)
+		if i > 0 {
+			name += "."
+		}
+		name += f.Name
+		p = f.Type
+	}
+	return name
+}
+
 func firstOptionalField(fields []*field) int {
 	for i, f := range fields {
 		if f.optional {
@@ -0,0 +1,222 @@
+package rlp
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Interface types can be decoded when their implementations are registered
+// with RegisterType. Values are then encoded in the typed envelope format of
+// EIP-2718: an RLP string containing the type byte followed by the RLP
+// encoding of the value. One implementation may be registered with
+// RegisterLegacyType, its values are encoded as-is without envelope.
+//
+// The decoder looks at the kind of the input to pick the implementation.
+// Lists are decoded into the legacy type, strings are decoded into the type
+// registered for their first byte. A nil interface value is encoded as an
+// empty list, which decodes to nil.
+//
+// For fields of a more general interface type, such as interface{}, the
+// implementations of another interface can be selected with the struct tag
+// rlp:"typed=NAME", where NAME is the interface type as printed by
+// reflect.Type.String, e.g. "types.TxData".
+
+// typedInterface holds the registered implementations of an interface.
+// It is never modified after registration, new registrations create a copy.
+type typedInterface struct {
+	iface  reflect.Type
+	legacy reflect.Type
+	byID   map[byte]reflect.Type
+	ids    map[reflect.Type]byte
+}
+
+// RegisterType registers typ as the implementation of the interface iface
+// identified by the type byte id. Type bytes must be below 0x80, so the
+// envelope can't be confused with other values.
+func RegisterType(iface reflect.Type, id byte, typ reflect.Type) {
+	if id >= 0x80 {
+		panic(fmt.Sprintf("rlp: type byte %#x of %v out of range", id, typ))
+	}
+	theTC.registerTyped(iface, typ, func(ti *typedInterface) {
+		if prev := ti.byID[id]; prev != nil && prev != typ {
+			panic(fmt.Sprintf("rlp: type byte %#x of %v already used by %v", id, iface, prev))
+		}
+		ti.byID[id] = typ
+		ti.ids[typ] = id
+	})
+}
+
+// RegisterLegacyType registers typ as the implementation of the interface
+// iface which is encoded without type byte. Values of the legacy type must
+// encode to an RLP list.
+func RegisterLegacyType(iface reflect.Type, typ reflect.Type) {
+	theTC.registerTyped(iface, typ, func(ti *typedInterface) {
+		ti.legacy = typ
+	})
+}
+
+func (c *typeCache) registerTyped(iface, typ reflect.Type, update func(*typedInterface)) {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("rlp: %v is not an interface type", iface))
+	}
+	if typ == nil || !typ.Implements(iface) {
+		panic(fmt.Sprintf("rlp: %v does not implement %v", typ, iface))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ti := &typedInterface{
+		iface: iface,
+		byID:  make(map[byte]reflect.Type),
+		ids:   make(map[reflect.Type]byte),
+	}
+	if prev := c.typed[iface.String()]; prev != nil {
+		if prev.iface != iface {
+			panic(fmt.Sprintf("rlp: interface name %v is ambiguous", iface))
+		}
+		ti.legacy = prev.legacy
+		for id, t := range prev.byID {
+			ti.byID[id] = t
+			ti.ids[t] = id
+		}
+	}
+	update(ti)
+
+	typed := make(map[string]*typedInterface, len(c.typed)+1)
+	for k, v := range c.typed {
+		typed[k] = v
+	}
+	typed[iface.String()] = ti
+	c.typed = typed
+
+	// Drop cached type info, see registerCodec.
+	c.cur.Store(make(map[typekey]*typeinfo))
+	c.values.Store(new(sync.Map))
+}
+
+// lookupTyped returns the registered implementations for an interface type.
+// If name is set, the implementations of the interface with that name are
+// returned instead. This must be called while generating type info.
+func (c *typeCache) lookupTyped(typ reflect.Type, name string) (*typedInterface, error) {
+	if name == "" {
+		return c.typed[typ.String()], nil
+	}
+	ti := c.typed[name]
+	if ti == nil {
+		return nil, fmt.Errorf("rlp: no types registered for interface %s", name)
+	}
+	if !ti.iface.AssignableTo(typ) {
+		return nil, fmt.Errorf("rlp: interface %v is not assignable to %v", ti.iface, typ)
+	}
+	return ti, nil
+}
+
+func (ti *typedInterface) write(val reflect.Value, w *encBuffer) error {
+	if val.IsNil() {
+		w.str = append(w.str, 0xC0)
+		return nil
+	}
+	eval := val.Elem()
+	writer, err := cachedWriter(eval.Type())
+	if err != nil {
+		return err
+	}
+	if eval.Type() == ti.legacy {
+		return writer(eval, w)
+	}
+	id, ok := ti.ids[eval.Type()]
+	if !ok {
+		return fmt.Errorf("rlp: type %v is not registered for %v", eval.Type(), ti.iface)
+	}
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+	buf.str = append(buf.str, id)
+	if err := writer(eval, buf); err != nil {
+		return err
+	}
+	w.writeBytes(buf.makeBytes())
+	return nil
+}
+
+func (ti *typedInterface) decode(s *Stream, val reflect.Value) error {
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == List && size == 0 {
+		// Nil value.
+		if _, err := s.List(); err != nil {
+			return wrapStreamError(err, val.Type())
+		}
+		if err := s.ListEnd(); err != nil {
+			return wrapStreamError(err, val.Type())
+		}
+		val.Set(reflect.Zero(val.Type()))
+		return nil
+	}
+	if kind == List {
+		if ti.legacy == nil {
+			return wrapStreamError(ErrExpectedString, val.Type())
+		}
+		return ti.decodeLegacy(s, val)
+	}
+
+	b, err := s.Bytes()
+	if err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	if len(b) == 0 {
+		return &decodeError{msg: "empty typed value", typ: val.Type()}
+	}
+	typ := ti.byID[b[0]]
+	if typ == nil {
+		return &decodeError{msg: fmt.Sprintf("unknown type byte %#x", b[0]), typ: val.Type()}
+	}
+	v := reflect.New(typ).Elem()
+	if err := decodePayload(s, b[1:], v); err != nil {
+		return err
+	}
+	val.Set(v)
+	return nil
+}
+
+// decodePayload decodes the content of a typed envelope into val. The payload
+// is read by a separate Stream, which inherits the limits and context of s.
+// Lists in the payload count as nested in the lists s is currently in.
+func decodePayload(s *Stream, payload []byte, val reflect.Value) error {
+	dec, err := cachedDecoder(val.Type())
+	if err != nil {
+		return err
+	}
+	r := bytes.NewReader(payload)
+	sub := streamPool.Get().(*Stream)
+	defer streamPool.Put(sub)
+	sub.Reset(r, uint64(len(payload)))
+	sub.limits = s.limits
+	sub.depth = s.depth + len(s.stack)
+	sub.ctx = s.ctx
+
+	if err := dec(sub, val); err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return &decodeError{msg: "input contains more than one value", typ: val.Type(), err: ErrMoreThanOneValue}
+	}
+	return nil
+}
+
+func (ti *typedInterface) decodeLegacy(s *Stream, val reflect.Value) error {
+	dec, err := cachedDecoder(ti.legacy)
+	if err != nil {
+		return err
+	}
+	v := reflect.New(ti.legacy).Elem()
+	if err := dec(s, v); err != nil {
+		return err
+	}
+	val.Set(v)
+	return nil
+}
@@ -0,0 +1,188 @@
+package rlp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type typedTestIface interface{ typedTest() }
+
+type typedTestA struct {
+	X uint
+	Y []uint
+}
+
+type typedTestB struct {
+	S string
+}
+
+type typedTestLegacy struct {
+	A, B uint
+}
+
+func (typedTestA) typedTest()       {}
+func (*typedTestB) typedTest()      {}
+func (typedTestLegacy) typedTest()  {}
+func (*typedTestCancel) typedTest() {}
+
+// typedTestCancel cancels typedTestCancelFunc when it is decoded.
+type typedTestCancel struct {
+	C cancelOnDecode
+	X uint
+}
+
+type cancelOnDecode struct{}
+
+var typedTestCancelFunc context.CancelFunc
+
+func (cancelOnDecode) DecodeRLP(s *Stream) error {
+	typedTestCancelFunc()
+	_, err := s.Raw()
+	return err
+}
+
+type typedTestOuter struct {
+	T typedTestIface
+}
+
+// typedTestStrict has no legacy type.
+type typedTestStrict interface{ typedTest() }
+
+type typedTestStrictOuter struct {
+	T typedTestStrict
+}
+
+func init() {
+	it := reflect.TypeOf((*typedTestIface)(nil)).Elem()
+	RegisterType(it, 1, reflect.TypeOf(typedTestA{}))
+	RegisterType(it, 2, reflect.TypeOf(&typedTestB{}))
+	RegisterType(it, 3, reflect.TypeOf(&typedTestCancel{}))
+	RegisterLegacyType(it, reflect.TypeOf(typedTestLegacy{}))
+
+	st := reflect.TypeOf((*typedTestStrict)(nil)).Elem()
+	RegisterType(st, 1, reflect.TypeOf(typedTestA{}))
+}
+
+func TestTypedRoundTrip(t *testing.T) {
+	tests := []struct {
+		val typedTestOuter
+		enc string
+	}{
+		{typedTestOuter{nil}, "C1C0"},
+		{typedTestOuter{typedTestA{X: 5, Y: []uint{1, 2}}}, "C78601C405C20102"},
+		{typedTestOuter{&typedTestB{S: "ab"}}, "C68502C3826162"},
+		{typedTestOuter{typedTestLegacy{A: 1, B: 2}}, "C3C20102"},
+	}
+	for _, test := range tests {
+		enc, err := EncodeToBytes(&test.val)
+		if err != nil {
+			t.Errorf("%+v: encode error: %v", test.val, err)
+			continue
+		}
+		if want := unhex(test.enc); !bytes.Equal(enc, want) {
+			t.Errorf("%+v: wrong encoding %x, want %x", test.val, enc, want)
+		}
+		// Start from a non-nil value to check that nil overwrites it.
+		dec := typedTestOuter{&typedTestB{}}
+		if err := DecodeBytes(enc, &dec); err != nil {
+			t.Errorf("%x: decode error: %v", enc, err)
+			continue
+		}
+		if !reflect.DeepEqual(dec, test.val) {
+			t.Errorf("%x: decoded %+v, want %+v", enc, dec, test.val)
+		}
+	}
+}
+
+func TestTypedNilWithoutLegacy(t *testing.T) {
+	enc, err := EncodeToBytes(&typedTestStrictOuter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex("C1C0"); !bytes.Equal(enc, want) {
+		t.Fatalf("wrong encoding %x, want %x", enc, want)
+	}
+	dec := typedTestStrictOuter{typedTestA{X: 1}}
+	if err := DecodeBytes(enc, &dec); err != nil {
+		t.Fatal("decode error:", err)
+	}
+	if dec.T != nil {
+		t.Fatalf("decoded %+v, want nil", dec.T)
+	}
+
+	// Non-empty lists are still rejected.
+	err = DecodeBytes(unhex("C3C20102"), &dec)
+	want := "rlp: expected input string or byte for rlp.typedTestStrict, decoding into rlp.typedTestStrictOuter.T"
+	if err == nil || err.Error() != want {
+		t.Fatalf("wrong error %v, want %s", err, want)
+	}
+}
+
+func TestTypedDecodeErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"C180", "rlp: empty typed value for rlp.typedTestIface, decoding into rlp.typedTestOuter.T"},
+		{"C109", "rlp: unknown type byte 0x9 for rlp.typedTestIface, decoding into rlp.typedTestOuter.T"},
+		{"C68501C3C101C0", "rlp: expected input string or byte for uint, decoding into rlp.typedTestOuter.T.X"},
+		{"C68501C205C080", "rlp: input contains more than one value for rlp.typedTestA, decoding into rlp.typedTestOuter.T"},
+	}
+	for _, test := range tests {
+		var dec typedTestOuter
+		err := DecodeBytes(unhex(test.input), &dec)
+		if err == nil {
+			t.Errorf("%s: expected error", test.input)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: wrong error\ngot:  %v\nwant: %v", test.input, err, test.err)
+		}
+	}
+}
+
+func TestTypedPayloadLimits(t *testing.T) {
+	// Envelope of typedTestA{X: 5, Y: []uint{1, 2, 3}} inside one list.
+	input := unhex("C88701C505C3010203")
+
+	decode := func(l Limits) error {
+		s := NewStream(bytes.NewReader(input), 0)
+		s.SetLimits(l)
+		var dec typedTestOuter
+		return s.Decode(&dec)
+	}
+	if err := decode(Limits{MaxDepth: 3, MaxElems: 3}); err != nil {
+		t.Fatalf("error within limits: %v", err)
+	}
+	if err := decode(Limits{MaxElems: 2}); !errors.Is(err, ErrTooManyElems) {
+		t.Errorf("MaxElems: got error %v, want %v", err, ErrTooManyElems)
+	}
+	// The payload list and its Y list are nested in the outer list.
+	if err := decode(Limits{MaxDepth: 2}); !errors.Is(err, ErrListTooDeep) {
+		t.Errorf("MaxDepth: got error %v, want %v", err, ErrListTooDeep)
+	}
+}
+
+func TestTypedPayloadContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	typedTestCancelFunc = cancel
+
+	// Envelope of typedTestCancel, which cancels the context while its
+	// payload is being decoded.
+	input := unhex("C58403C2C005")
+	s := NewStream(bytes.NewReader(input), 0)
+	s.SetContext(ctx)
+	var dec typedTestOuter
+	err := s.Decode(&dec)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if strings.Count(err.Error(), "typedTestOuter") > 1 {
+		t.Errorf("error repeats root type: %v", err)
+	}
+}
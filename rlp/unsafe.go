@@ -1,3 +1,6 @@
+//go:build !purego && !nounsafe
+// +build !purego,!nounsafe
+
 package rlp
 
 import (
@@ -5,11 +8,8 @@ import (
 	"unsafe"
 )
 
+// byteArrayBytes returns a slice of the byte array v, which must be
+// addressable.
 func byteArrayBytes(value reflect.Value, length int) []byte {
-	var v []byte
-	hr := (*reflect.SliceHeader)(unsafe.Pointer(&v))
-	hr.Data = value.UnsafeAddr()
-	hr.Len = length
-	hr.Cap = length
-	return v
+	return unsafe.Slice((*byte)(unsafe.Pointer(value.UnsafeAddr())), length)
 }
@@ -0,0 +1,191 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Value is a generic RLP value tree. A Value is either a string or a list
+// of values. It can hold any RLP data without knowing its schema.
+//
+// The zero Value is the empty string.
+//
+// In JSON, lists are represented as arrays and strings as 0x-prefixed hex
+// strings, e.g. the encoding of ["dog", [1]] is
+//
+//	["0x646f67", ["0x01"]]
+type Value struct {
+	isList bool
+	str    []byte
+	elems  []Value
+}
+
+// StringValue creates a string value holding b.
+func StringValue(b []byte) Value {
+	return Value{str: b}
+}
+
+// ListValue creates a list value containing the given elements.
+func ListValue(elems ...Value) Value {
+	if elems == nil {
+		elems = []Value{}
+	}
+	return Value{isList: true, elems: elems}
+}
+
+// ParseValue parses the RLP encoding of a single value into a tree. The
+// input must be canonical and must not contain trailing data.
+func ParseValue(b []byte) (Value, error) {
+	v, rest, err := parseValue(b)
+	if err != nil {
+		return Value{}, err
+	}
+	if len(rest) > 0 {
+		return Value{}, ErrMoreThanOneValue
+	}
+	return v, nil
+}
+
+func parseValue(b []byte) (Value, []byte, error) {
+	k, content, rest, err := Split(b)
+	if err != nil {
+		return Value{}, b, err
+	}
+	if k != List {
+		return StringValue(content), rest, nil
+	}
+	elems := []Value{}
+	for len(content) > 0 {
+		var v Value
+		if v, content, err = parseValue(content); err != nil {
+			return Value{}, b, err
+		}
+		elems = append(elems, v)
+	}
+	return ListValue(elems...), rest, nil
+}
+
+// IsList reports whether v is a list.
+func (v Value) IsList() bool {
+	return v.isList
+}
+
+// Bytes returns the content of a string value. It returns nil for lists.
+func (v Value) Bytes() []byte {
+	return v.str
+}
+
+// Elems returns the elements of a list value. It returns nil for strings.
+func (v Value) Elems() []Value {
+	return v.elems
+}
+
+// Len returns the number of elements of a list or the length of a string.
+func (v Value) Len() int {
+	if v.isList {
+		return len(v.elems)
+	}
+	return len(v.str)
+}
+
+// Encode returns the RLP encoding of v.
+func (v Value) Encode() []byte {
+	w := NewEncoderBuffer(nil)
+	v.encode(w)
+	b := w.ToBytes()
+	w.Flush()
+	return b
+}
+
+// EncodeRLP implements Encoder.
+func (v Value) EncodeRLP(w io.Writer) error {
+	buf := NewEncoderBuffer(w)
+	v.encode(buf)
+	return buf.Flush()
+}
+
+func (v Value) encode(w EncoderBuffer) {
+	if !v.isList {
+		w.WriteBytes(v.str)
+		return
+	}
+	index := w.List()
+	for _, e := range v.elems {
+		e.encode(w)
+	}
+	w.ListEnd(index)
+}
+
+// DecodeRLP implements Decoder.
+func (v *Value) DecodeRLP(s *Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	val, err := ParseValue(raw)
+	if err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil), nil
+}
+
+func (v Value) appendJSON(b []byte) []byte {
+	if !v.isList {
+		b = append(b, `"0x`...)
+		b = append(b, hex.EncodeToString(v.str)...)
+		return append(b, '"')
+	}
+	b = append(b, '[')
+	for i, e := range v.elems {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = e.appendJSON(b)
+	}
+	return append(b, ']')
+}
+
+var errJSONValue = errors.New("rlp: JSON value must be a 0x-prefixed hex string or an array")
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Value) UnmarshalJSON(input []byte) error {
+	input = bytes.TrimSpace(input)
+	if len(input) == 0 {
+		return errJSONValue
+	}
+	switch input[0] {
+	case '[':
+		var elems []Value
+		if err := json.Unmarshal(input, &elems); err != nil {
+			return err
+		}
+		*v = ListValue(elems...)
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(input, &s); err != nil {
+			return err
+		}
+		if len(s) < 2 || (s[:2] != "0x" && s[:2] != "0X") {
+			return fmt.Errorf("rlp: JSON string %q lacks 0x prefix", s)
+		}
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return fmt.Errorf("rlp: invalid hex string in JSON: %v", err)
+		}
+		*v = StringValue(b)
+		return nil
+	default:
+		return errJSONValue
+	}
+}
@@ -0,0 +1,166 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestValueParse(t *testing.T) {
+	tests := []struct {
+		input string
+		json  string
+	}{
+		{input: "80", json: `"0x"`},
+		{input: "05", json: `"0x05"`},
+		{input: "83646F67", json: `"0x646f67"`},
+		{input: "C0", json: `[]`},
+		{input: "C4C0C1C0C0", json: `[[],[[]],[]]`},
+		{input: "C88363617483646F67", json: `["0x636174","0x646f67"]`},
+		{input: "CA83646F67C501C2028080", json: `["0x646f67",["0x01",["0x02","0x"],"0x"]]`},
+		{
+			input: "B8380000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+			json:  `"0x` + strings.Repeat("00", 56) + `"`,
+		},
+	}
+	for _, test := range tests {
+		input := unhex(test.input)
+		v, err := ParseValue(input)
+		if err != nil {
+			t.Errorf("%s: parse error: %v", test.input, err)
+			continue
+		}
+		if enc := v.Encode(); !bytes.Equal(enc, input) {
+			t.Errorf("%s: Encode returned %x", test.input, enc)
+		}
+		js, err := json.Marshal(v)
+		if err != nil {
+			t.Errorf("%s: MarshalJSON error: %v", test.input, err)
+			continue
+		}
+		if string(js) != test.json {
+			t.Errorf("%s: wrong JSON %s, want %s", test.input, js, test.json)
+		}
+		var dec Value
+		if err := json.Unmarshal(js, &dec); err != nil {
+			t.Errorf("%s: UnmarshalJSON error: %v", test.input, err)
+			continue
+		}
+		if enc := dec.Encode(); !bytes.Equal(enc, input) {
+			t.Errorf("%s: value from JSON encodes to %x", test.input, enc)
+		}
+	}
+}
+
+func TestValueParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{input: "", err: io.ErrUnexpectedEOF},
+		{input: "0102", err: ErrMoreThanOneValue},
+		{input: "C0C0", err: ErrMoreThanOneValue},
+		{input: "8105", err: ErrCanonSize},
+		{input: "B800", err: ErrCanonSize},
+		{input: "C3C28105", err: ErrCanonSize},
+		{input: "C301", err: ErrValueTooLarge},
+		{input: "81", err: ErrValueTooLarge},
+		{input: "C2C301", err: ErrValueTooLarge},
+	}
+	for _, test := range tests {
+		_, err := ParseValue(unhex(test.input))
+		if err != test.err {
+			t.Errorf("%s: got error %v, want %v", test.input, err, test.err)
+		}
+	}
+}
+
+func TestValueNavigation(t *testing.T) {
+	v, err := ParseValue(unhex("CA83646F67C501C2028080"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.IsList() || v.Len() != 2 || v.Bytes() != nil {
+		t.Fatalf("wrong outer list: IsList %t, Len %d, Bytes %x", v.IsList(), v.Len(), v.Bytes())
+	}
+	dog := v.Elems()[0]
+	if dog.IsList() || dog.Len() != 3 || string(dog.Bytes()) != "dog" || dog.Elems() != nil {
+		t.Errorf("wrong string element: %+v", dog)
+	}
+	inner := v.Elems()[1]
+	if !inner.IsList() || inner.Len() != 3 {
+		t.Fatalf("wrong inner list: %+v", inner)
+	}
+	if b := inner.Elems()[1].Elems()[0].Bytes(); !bytes.Equal(b, []byte{2}) {
+		t.Errorf("wrong nested element %x", b)
+	}
+	if empty := inner.Elems()[2]; empty.IsList() || empty.Len() != 0 {
+		t.Errorf("wrong empty string: %+v", empty)
+	}
+
+	// The zero value is the empty string, ListValue() is the empty list.
+	if enc := (Value{}).Encode(); !bytes.Equal(enc, unhex("80")) {
+		t.Errorf("zero Value encodes to %x", enc)
+	}
+	if enc := ListValue().Encode(); !bytes.Equal(enc, unhex("C0")) {
+		t.Errorf("empty ListValue encodes to %x", enc)
+	}
+	built := ListValue(StringValue([]byte("dog")), ListValue(StringValue([]byte{1}), ListValue(StringValue([]byte{2}), Value{}), Value{}))
+	if enc := built.Encode(); !bytes.Equal(enc, unhex("CA83646F67C501C2028080")) {
+		t.Errorf("built value encodes to %x", enc)
+	}
+}
+
+func TestValueEncoderDecoder(t *testing.T) {
+	type withValue struct {
+		A uint
+		V Value
+		B string
+	}
+	input := unhex("CB01C501C202808083616263")
+	var dec withValue
+	if err := DecodeBytes(input, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.A != 1 || dec.B != "abc" || !dec.V.IsList() || dec.V.Len() != 3 {
+		t.Fatalf("wrong decoded value %+v", dec)
+	}
+	enc, err := EncodeToBytes(&dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, input) {
+		t.Fatalf("re-encoding gives %x, want %x", enc, input)
+	}
+
+	// Non-canonical content is rejected by DecodeRLP.
+	if err := DecodeBytes(unhex("C601C381050580"), &dec); err == nil {
+		t.Fatal("no error for non-canonical value")
+	}
+}
+
+func TestValueUnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{input: `"646f67"`, err: `rlp: JSON string "646f67" lacks 0x prefix`},
+		{input: `"0x6"`, err: "rlp: invalid hex string in JSON: encoding/hex: odd length hex string"},
+		{input: `"0xzz"`, err: "rlp: invalid hex string in JSON: encoding/hex: invalid byte: U+007A 'z'"},
+		{input: `5`, err: errJSONValue.Error()},
+		{input: `null`, err: errJSONValue.Error()},
+		{input: `{}`, err: errJSONValue.Error()},
+		{input: `["0x01", 2]`, err: errJSONValue.Error()},
+	}
+	for _, test := range tests {
+		var v Value
+		err := json.Unmarshal([]byte(test.input), &v)
+		if err == nil {
+			t.Errorf("%s: expected error", test.input)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: wrong error\ngot:  %v\nwant: %s", test.input, err, test.err)
+		}
+	}
+}